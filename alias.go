@@ -0,0 +1,365 @@
+package gocli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrAliasNotFound is the error that returns when an alias is not defined.
+	ErrAliasNotFound = errors.New("alias not found")
+	// ErrAliasInvalid is the error that returns when an alias has an invalid name or value.
+	ErrAliasInvalid = errors.New("invalid alias")
+	// ErrAliasShadowed is the error that returns when an alias has the name of a command or a group.
+	ErrAliasShadowed = errors.New("alias shadows a command")
+)
+
+// patternAliasName is the regexp pattern of the name of an alias.
+var patternAliasName = regexp.MustCompile(`^\w[\w-]*$`)
+
+// AliasFinder is the interface that wraps the method to find a user alias.
+// It is optional for a Commander, the CLI only expands aliases if the Commander implements it.
+type AliasFinder interface {
+	// FindAlias is the method that finds a user alias by name.
+	// - ok is false if no alias has the name
+	FindAlias(name string) (a Alias, ok bool)
+}
+
+// Alias is the struct that represents a shortcut defined by the user.
+type Alias struct {
+	// Name is the name of the alias, given as the first arg.
+	Name string
+	// Value is the args the name expands to, e.g. "deploy --env prod".
+	// - a value starting with ! is a macro: a shell command run with the rest of the args
+	Value string
+	// Shadowed is the flag that indicates if a command or a group has the name of the alias, set by FindAlias.
+	// - a shadowed alias is never expanded
+	Shadowed bool
+}
+
+// Macro is the method that returns if the alias is a shell command.
+func (a Alias) Macro() (ok bool) {
+	ok = strings.HasPrefix(a.Value, "!")
+	return
+}
+
+// Args is the method that returns the args the alias expands to.
+// - the value is split with the quoting rules of the response files
+func (a Alias) Args() (args []string, err error) {
+	args, err = splitResponseFile(a.Value)
+	if err != nil {
+		err = fmt.Errorf("%w: %s: %s", ErrAliasInvalid, a.Name, err)
+	}
+	return
+}
+
+// DefaultAliasesPath is the function that returns the default config file of the aliases of an app.
+// - <user config dir>/<app>/config, e.g. ~/.config/app/config on linux
+func DefaultAliasesPath(app string) (path string, err error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return
+	}
+	path = filepath.Join(dir, app, "config")
+	return
+}
+
+// Aliases is the struct that represents the aliases defined in a config file.
+// - an alias is a line `alias <name> = <value>`, other lines of the file are kept as they are
+type Aliases struct {
+	// Path is the path of the config file.
+	Path string
+}
+
+// List is the method that returns the aliases of the config file, in file order.
+// - a missing file has no aliases
+func (a Aliases) List() (aliases []Alias, err error) {
+	lines, err := a.lines()
+	if err != nil {
+		return
+	}
+	for _, line := range lines {
+		if alias, ok := parseAliasLine(line); ok {
+			aliases = append(aliases, alias)
+		}
+	}
+	return
+}
+
+// Find is the method that finds an alias by name.
+func (a Aliases) Find(name string) (alias Alias, ok bool, err error) {
+	aliases, err := a.List()
+	if err != nil {
+		return
+	}
+	for _, al := range aliases {
+		if al.Name == name {
+			alias = al
+			ok = true
+		}
+	}
+	return
+}
+
+// Set is the method that defines an alias, replacing the one with the same name.
+// - the dir of the config file is created if it does not exist
+func (a Aliases) Set(alias Alias) (err error) {
+	if !patternAliasName.MatchString(alias.Name) || strings.TrimSpace(alias.Value) == "" {
+		err = fmt.Errorf("%w: %q", ErrAliasInvalid, alias.Name)
+		return
+	}
+	if !alias.Macro() {
+		if _, err = alias.Args(); err != nil {
+			return
+		}
+	}
+
+	lines, err := a.lines()
+	if err != nil {
+		return
+	}
+	line := "alias " + alias.Name + " = " + strings.TrimSpace(alias.Value)
+	var replaced bool
+	for i := range lines {
+		if al, ok := parseAliasLine(lines[i]); ok && al.Name == alias.Name {
+			lines[i] = line
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, line)
+	}
+
+	err = a.write(lines)
+	return
+}
+
+// Delete is the method that removes an alias.
+func (a Aliases) Delete(name string) (err error) {
+	lines, err := a.lines()
+	if err != nil {
+		return
+	}
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if al, ok := parseAliasLine(line); ok && al.Name == name {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) == len(lines) {
+		err = fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+		return
+	}
+
+	err = a.write(kept)
+	return
+}
+
+// lines is the method that returns the lines of the config file, nil if it does not exist.
+func (a Aliases) lines() (lines []string, err error) {
+	content, err := os.ReadFile(a.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+	lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	return
+}
+
+// write is the method that writes the lines of the config file.
+func (a Aliases) write(lines []string) (err error) {
+	err = os.MkdirAll(filepath.Dir(a.Path), 0o755)
+	if err != nil {
+		return
+	}
+	err = os.WriteFile(a.Path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+	return
+}
+
+// parseAliasLine is the function that parses a line `alias <name> = <value>` of the config file.
+func parseAliasLine(line string) (alias Alias, ok bool) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(line), "alias ")
+	if !found {
+		return
+	}
+	name, value, found := strings.Cut(rest, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !found || !patternAliasName.MatchString(name) || value == "" {
+		return
+	}
+
+	alias = Alias{Name: name, Value: value}
+	ok = true
+	return
+}
+
+// EnableAliases is the method that enables the user aliases defined in a config file.
+// - the path is the config file, see DefaultAliasesPath
+// - it adds the group "alias" with the commands set, list (default) and delete
+// - set and delete are Local, so aliases and macros can only be defined from the command line
+func (c *CommanderManager) EnableAliases(path string) {
+	(*c).Aliases = &Aliases{Path: path}
+
+	group := c.Group("alias", "manages the user aliases")
+	group.AddCommand(Command{
+		Name:        "set",
+		Description: "defines an alias, e.g. alias set dp deploy --env prod",
+		RawArgs:     true,
+		Local:       true,
+		Handler: func(i Input) (err error) {
+			args := aliasCommandArgs(i)
+			if len(args) < 2 {
+				err = fmt.Errorf("%w: usage: alias set <name> <value>", ErrAliasInvalid)
+				return
+			}
+			if c.shadows(args[0]) {
+				err = fmt.Errorf("%w: %s", ErrAliasShadowed, args[0])
+				return
+			}
+			value := strings.Join(args[1:], " ")
+			if !strings.HasPrefix(args[1], "!") {
+				value = quoteArgs(args[1:])
+			}
+			err = c.Aliases.Set(Alias{Name: args[0], Value: value})
+			return
+		},
+	})
+	group.AddCommand(Command{
+		Name:        "list",
+		Description: "lists the user aliases",
+		Handler: func(i Input) (err error) {
+			w := i.Writer
+			if w == nil {
+				w = os.Stdout
+			}
+
+			aliases, err := c.Aliases.List()
+			if err != nil {
+				return
+			}
+			if len(aliases) == 0 {
+				_, err = fmt.Fprintln(w, "no aliases defined")
+				return
+			}
+			var b strings.Builder
+			writeHelpSection(&b, i.Style, "Aliases:", c.aliasRows(i.Style, aliases))
+			_, err = fmt.Fprint(w, strings.TrimPrefix(b.String(), "\n"))
+			return
+		},
+	})
+	group.AddCommand(Command{
+		Name:        "delete",
+		Description: "removes an alias, e.g. alias delete dp",
+		RawArgs:     true,
+		Local:       true,
+		Handler: func(i Input) (err error) {
+			args := aliasCommandArgs(i)
+			if len(args) != 1 {
+				err = fmt.Errorf("%w: usage: alias delete <name>", ErrAliasInvalid)
+				return
+			}
+			err = c.Aliases.Delete(args[0])
+			return
+		},
+	})
+	group.(GroupConfigurer).SetDefault("list")
+}
+
+// FindAlias is the method that finds a user alias by name.
+// - an alias with the name of a command or a group is returned as shadowed
+func (c *CommanderManager) FindAlias(name string) (a Alias, ok bool) {
+	if c.Aliases == nil {
+		return
+	}
+	a, ok, err := c.Aliases.Find(name)
+	if err != nil || !ok {
+		ok = false
+		return
+	}
+	a.Shadowed = c.shadows(name)
+	return
+}
+
+// shadows is the method that returns if a name is taken by a command, a group, a plugin or a built-in command of the root.
+func (c *CommanderManager) shadows(name string) (ok bool) {
+	if _, err := c.Cmds.FindCommand(name); err == nil {
+		ok = true
+		return
+	}
+	if c.Plugins != nil {
+		if _, ok = c.Plugins.Find(name); ok {
+			return
+		}
+	}
+	ok = c.findGroup(name) != nil || strings.HasPrefix(name, "__")
+	return
+}
+
+// aliasRows is the method that returns the rows of the aliases for the help, marking the shadowed ones.
+func (c *CommanderManager) aliasRows(s Styler, aliases []Alias) (rows [][2]string) {
+	rows = make([][2]string, 0, len(aliases))
+	for _, a := range aliases {
+		value := a.Value
+		if c.shadows(a.Name) {
+			value += " " + s.Dim("(shadowed by a command, ignored)")
+		}
+		rows = append(rows, [2]string{a.Name, value})
+	}
+	return
+}
+
+// quoteArgs is the function that joins args with spaces, quoting the ones that would be split.
+// - the quotes follow the rules of the response files, so Alias.Args returns the same args
+func quoteArgs(args []string) (s string) {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\#") {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	s = strings.Join(quoted, " ")
+	return
+}
+
+// aliasCommandArgs is the function that returns the args given after the chain and the command.
+func aliasCommandArgs(i Input) (args []string) {
+	args = i.Args
+	if n := len(i.CommandInput.Chain) + 1; len(args) >= n {
+		args = args[n:]
+	}
+	return
+}
+
+// runMacro is the method that runs an alias macro as a shell command, with the args after the alias.
+// - the args are given to the shell command as its positional parameters, "$@"
+func (c CLI) runMacro(ctx context.Context, a Alias, args []string) (err error) {
+	script := strings.TrimPrefix(a.Value, "!") + ` "$@"`
+	cmd := exec.CommandContext(ctx, "sh", append([]string{"-c", script, a.Name}, args...)...)
+	cmd.Stdin = c.reader()
+	cmd.Stdout = c.writer()
+	cmd.Stderr = c.errWriter()
+	err = cmd.Run()
+
+	// exit code
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = &ExitError{Code: exitErr.ExitCode()}
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("alias %s: %w", a.Name, err)
+	}
+	return
+}
@@ -0,0 +1,188 @@
+package gocli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_Run_Aliases is the test for the user aliases.
+func TestCLI_Run_Aliases(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests, with the aliases in the config file
+	// - the flags given to the deploy command are written to flags
+	newCLI := func(t *testing.T, config string, flags *map[string]any) (cli gocli.CLI, path string, w, ew *bytes.Buffer) {
+		path = filepath.Join(t.TempDir(), "app", "config")
+		if config != "" {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte(config), 0o644))
+		}
+
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name:  "deploy",
+			Flags: gocli.Flags{{Name: "env"}, {Name: "region"}},
+			Handler: func(i gocli.Input) (err error) {
+				*flags = i.Flags
+				return
+			},
+		})
+		cm.EnableAliases(path)
+
+		w, ew = &bytes.Buffer{}, &bytes.Buffer{}
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Writer = w
+		cli.ErrWriter = ew
+		return
+	}
+
+	t.Run("success - case 01: alias is expanded with the rest of the args", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, _, _, _ := newCLI(t, "# aliases\nalias dp = deploy --env prod\n", &flags)
+		os.Args = []string{"app.exe", "dp", "--region", "eu"}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"env": "prod", "region": "eu"}, flags)
+	})
+
+	t.Run("success - case 02: alias set, list and delete", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, path, w, _ := newCLI(t, "color = never\n", &flags)
+
+		// act
+		os.Args = []string{"app.exe", "alias", "set", "dp", "deploy", "--env", "prod"}
+		errSet := cli.Run()
+		os.Args = []string{"app.exe", "alias", "set", "eu", "deploy", "--region", "eu west"}
+		errSetQuoted := cli.Run()
+		os.Args = []string{"app.exe", "alias", "delete", "dp"}
+		errDelete := cli.Run()
+		os.Args = []string{"app.exe", "alias", "list"}
+		errList := cli.Run()
+
+		// assert
+		require.NoError(t, errSet)
+		require.NoError(t, errSetQuoted)
+		require.NoError(t, errDelete)
+		require.NoError(t, errList)
+		require.Equal(t, "Aliases:\n  eu  deploy --region 'eu west'\n", w.String())
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "color = never\nalias eu = deploy --region 'eu west'\n", string(content))
+	})
+
+	t.Run("success - case 03: alias shadowed by a command is ignored with a warning", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, _, _, ew := newCLI(t, "alias deploy = deploy --env prod\n", &flags)
+		os.Args = []string{"app.exe", "deploy", "--env", "dev"}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"env": "dev"}, flags)
+		require.Contains(t, ew.String(), "alias \"deploy\" is shadowed by a command with the same name, it is ignored")
+	})
+
+	t.Run("success - case 04: aliases are completed and shown in the help", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, _, w, _ := newCLI(t, "alias dp = deploy --env prod\nalias deploy = deploy\n", &flags)
+
+		// act
+		os.Args = []string{"app.exe", "__complete", "d"}
+		errComplete := cli.Run()
+		completed := w.String()
+		w.Reset()
+		os.Args = []string{"app.exe", "__complete", "dp", "--"}
+		errCompleteFlags := cli.Run()
+		completedFlags := w.String()
+		w.Reset()
+		os.Args = []string{"app.exe", "--help"}
+		errHelp := cli.Run()
+
+		// assert
+		require.NoError(t, errComplete)
+		require.Equal(t, "deploy\ndp\n", completed)
+		require.NoError(t, errCompleteFlags)
+		require.Equal(t, "--env\n--region\n", completedFlags)
+		require.NoError(t, errHelp)
+		require.Contains(t, w.String(), "User Aliases:\n"+
+			"  dp      deploy --env prod\n"+
+			"  deploy  deploy (shadowed by a command, ignored)\n")
+	})
+
+	t.Run("success - case 05: macro runs a shell command with the args", func(t *testing.T) {
+		if _, err := os.Stat("/bin/sh"); err != nil {
+			t.Skip("macros require /bin/sh")
+		}
+
+		// arrange
+		var flags map[string]any
+		cli, _, w, _ := newCLI(t, "alias hi = !echo hello\n", &flags)
+		os.Args = []string{"app.exe", "hi", "gopher"}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "hello gopher\n", w.String())
+	})
+
+	t.Run("failure - case 01: alias set with the name of a command", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, path, _, _ := newCLI(t, "", &flags)
+		os.Args = []string{"app.exe", "alias", "set", "deploy", "deploy", "--env", "prod"}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrAliasShadowed)
+		require.NoFileExists(t, path)
+	})
+
+	t.Run("failure - case 02: alias delete of an undefined alias", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, _, _, _ := newCLI(t, "", &flags)
+		os.Args = []string{"app.exe", "alias", "delete", "dp"}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrAliasNotFound)
+		require.EqualError(t, err, "alias not found: dp")
+	})
+
+	t.Run("failure - case 03: alias set with the name of a plugin", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli, path, _, _ := newCLI(t, "", &flags)
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-hello", "true")
+		cli.Commander.(*gocli.CommanderManager).EnablePlugins(dir)
+		os.Args = []string{"app.exe", "alias", "set", "hello", "deploy", "--env", "prod"}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrAliasShadowed)
+		require.NoFileExists(t, path)
+	})
+}
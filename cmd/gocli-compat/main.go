@@ -0,0 +1,52 @@
+// Command gocli-compat compares two manifests written by `app __manifest --format json`
+// and exits with a non-zero code if the new one has breaking changes.
+//
+// Usage: gocli-compat <old.json> <new.json>
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/gocli/compat"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: gocli-compat <old.json> <new.json>")
+		os.Exit(2)
+	}
+
+	// manifests
+	old, err := readManifest(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(2)
+	}
+	next, err := readManifest(os.Args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(2)
+	}
+
+	// report
+	report := compat.Compare(old, next)
+	fmt.Print(report)
+	os.Exit(report.ExitCode())
+}
+
+// readManifest is the function that reads a manifest from a json file.
+func readManifest(path string) (m gocli.Manifest, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	return
+}
@@ -1,5 +1,7 @@
 package gocli
 
+import "time"
+
 // CommandHandler is the type that represents a command function.
 type CommandHandler func(i Input) (err error)
 
@@ -9,8 +11,32 @@ type Command struct {
 	Name string
 	// Description is the description of the command.
 	Description string
+	// Aliases are other names the command can be run with.
+	Aliases []string
+	// Examples are examples of command lines running the command.
+	Examples []string
 	// Handler is the handler of the command.
 	Handler CommandHandler
+	// Flags are the specifications of the flags of the command.
+	Flags Flags
+	// FlagGroups are the relationships between the flags of the command, e.g. mutually exclusive flags.
+	FlagGroups []FlagGroup
+	// Timeout is the time the handler has to run, 0 for no timeout.
+	// - the context of the handler is cancelled when it runs out, it can be overridden with the --timeout flag
+	Timeout time.Duration
+	// RawArgs is the flag that indicates if the command accepts any args, even if the parser rejects them.
+	// - the args are given as is in Input.Args
+	// - the command parses its own args: the flags are not checked nor defaulted, they are only used for help and completion
+	RawArgs bool
+	// Local is the flag that indicates if the command only runs from the command line of the user.
+	// - it is not exposed by Serve nor as an MCP tool, e.g. a command that changes the config of the user
+	Local bool
+
+	// Hidden is the flag that indicates if the command is left out of help and completion.
+	// - a hidden command is still found by FindHandler
+	Hidden bool
+	// Deprecated is the deprecation of the command, nil if it is not deprecated.
+	Deprecated *Deprecated
 }
 
 // Commands is the type that represents a list of commands.
@@ -18,11 +44,22 @@ type Commands []Command
 
 // FindHandler is the method that finds a handler by name.
 func (c Commands) FindHandler(commandName string) (h CommandHandler, err error) {
+	cmd, err := c.FindCommand(commandName)
+	if err != nil {
+		return
+	}
+
+	h = cmd.Handler
+	return
+}
+
+// FindCommand is the method that finds a command by name or alias.
+func (c Commands) FindCommand(commandName string) (cmd Command, err error) {
 	// check if exists
 	var exists bool
-	for _, cmd := range c {
-		if cmd.Name == commandName {
-			h = cmd.Handler
+	for _, cm := range c {
+		if cm.Name == commandName || contains(cm.Aliases, commandName) {
+			cmd = cm
 			exists = true
 			break
 		}
@@ -22,6 +22,20 @@ func TestCommands_FindHandler(t *testing.T) {
 		require.NotNil(t, h)
 	})
 
+	t.Run("success - case 02: command is found by alias", func(t *testing.T) {
+		// arrange
+		cmds := Commands([]Command{
+			{Name: "cmd1", Aliases: []string{"c1"}, Handler: func(i Input) (err error) { return }},
+		})
+
+		// act
+		cmd, err := cmds.FindCommand("c1")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "cmd1", cmd.Name)
+	})
+
 	t.Run("error - case 01: command does not exist", func(t *testing.T) {
 		// arrange
 		cmds := Commands([]Command{
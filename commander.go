@@ -13,6 +13,7 @@ var (
 type Commander interface {
 	// Read-Operations
 	// FindHandler is the method that finds a handler by name.
+	// - the empty command name is the handler of the group of the chain itself
 	FindHandler(commandName string, commandChain ...string) (h CommandHandler, err error)
 
 	// Write-Operations
@@ -20,4 +21,69 @@ type Commander interface {
 	AddCommand(command Command) (err error)
 	// Group is the method that groups a list of commands.
 	Group(name string, description string) (cm Commander)
+}
+
+// GroupConfigurer is the interface that wraps the methods to configure a group.
+// It is optional for a Commander, the groups returned by CommanderManager.Group implement it,
+// e.g. cm.Group("db", "database commands").(gocli.GroupConfigurer).SetDefault("status").
+type GroupConfigurer interface {
+	// SetHidden is the method that sets if the group is left out of help and completion.
+	SetHidden(hidden bool)
+	// SetDeprecated is the method that sets the deprecation of the group.
+	SetDeprecated(d Deprecated)
+	// SetHandler is the method that sets the handler run when the group itself is run.
+	SetHandler(h CommandHandler)
+	// SetDefault is the method that sets the command run when the group itself is run.
+	SetDefault(commandName string)
+	// AddFlag is the method that adds a persistent flag to the group, accepted by every command under it.
+	AddFlag(flag Flag)
+}
+
+// CommandPath is the struct that represents a command found with its groups.
+type CommandPath struct {
+	// Managers are the command managers from the root to the group of the command.
+	Managers []*CommanderManager
+	// Command is the command.
+	Command Command
+}
+
+// Chain is the method that returns the names of the groups of the path, without the root.
+func (p CommandPath) Chain() (chain []string) {
+	chain = []string{}
+	for i := 1; i < len(p.Managers); i++ {
+		chain = append(chain, p.Managers[i].Name)
+	}
+	return
+}
+
+// Flags is the method that returns the flags accepted by the command of the path.
+// - the flags of the command first, then the persistent flags of its groups, from the nearest to the root
+// - a flag of the command, or of a nearer group, shadows a persistent flag with the same name
+func (p CommandPath) Flags() (flags Flags) {
+	flags = inheritFlags(p.Command.Flags, p.Managers)
+	return
+}
+
+// inheritFlags is the function that returns the flags with the persistent flags of the groups appended.
+// - the groups are given from the root, the nearest ones are appended first
+// - flags already present are not appended again
+func inheritFlags(own Flags, managers []*CommanderManager) (flags Flags) {
+	flags = append(Flags{}, own...)
+	for i := len(managers) - 1; i >= 0; i-- {
+		for _, f := range managers[i].Flags {
+			if _, ok := flags.Find(f.Name); ok {
+				continue
+			}
+			flags = append(flags, f)
+		}
+	}
+	return
+}
+
+// CommandPathFinder is the interface that wraps the method to find a command with its groups.
+// It is optional for a Commander, the CLI only uses the specification of the command
+// (deprecation, flags, etc.) if the Commander implements it.
+type CommandPathFinder interface {
+	// FindCommandPath is the method that finds a command and the groups in its chain.
+	FindCommandPath(commandName string, commandChain ...string) (p CommandPath, err error)
 }
\ No newline at end of file
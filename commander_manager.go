@@ -1,5 +1,7 @@
 package gocli
 
+import "os"
+
 // NewCommanderManager is the function that creates a new command manager.
 func NewCommanderManager(name, description string) (cm *CommanderManager) {
 	cm = &CommanderManager{
@@ -19,6 +21,26 @@ type CommanderManager struct {
 	Cmds Commands
 	// CommandManagers is the command composite of the command composite.
 	CommandManagers []*CommanderManager
+
+	// Hidden is the flag that indicates if the group is left out of help and completion.
+	// - the commands of a hidden group are still found by FindHandler
+	Hidden bool
+	// Deprecated is the deprecation of the group, nil if it is not deprecated.
+	Deprecated *Deprecated
+
+	// Handler is the handler run when the group itself is run, e.g. `app db`.
+	Handler CommandHandler
+	// Default is the name of the command run when the group itself is run and it has no handler.
+	Default string
+	// Flags are the persistent flags of the group, accepted by every command under it.
+	// - e.g. --profile or --region, given in any position of the command line
+	Flags Flags
+	// Plugins are the external plugin commands, run for unknown top-level commands.
+	// - nil if they are not enabled, see EnablePlugins
+	Plugins *Plugins
+	// Aliases are the user aliases, expanded when given as the first arg.
+	// - nil if they are not enabled, see EnableAliases
+	Aliases *Aliases
 	
 	// TODO: implement indexed commands
 	// ...
@@ -26,60 +48,144 @@ type CommanderManager struct {
 
 // FindHandler is the method that finds a handler by name.
 func (c *CommanderManager) FindHandler(commandName string, commandChain ...string) (h CommandHandler, err error) {
-	// find command manager
-	cmg, err := c.FindCommandManager(commandChain...)
+	// find command
+	p, err := c.FindCommandPath(commandName, commandChain...)
 	if err != nil {
 		return
 	}
 
+	h = p.Command.Handler
+	return
+}
+
+// FindCommandPath is the method that finds a command and the command managers in its chain.
+// - a group can also be run as a command, e.g. `app db`, then its command is:
+// the handler of the group, its default command or the help of the group, in that order
+// - the empty command name is the group of the chain itself, e.g. the root for a bare `app`
+func (c *CommanderManager) FindCommandPath(commandName string, commandChain ...string) (p CommandPath, err error) {
+	// find command managers
+	managers, err := c.findCommandManagers(commandChain...)
+	if err != nil {
+		return
+	}
+	cmg := managers[len(managers)-1]
+
 	// fetch command
-	h, err = cmg.Cmds.FindHandler(commandName)
+	cmd, err := cmg.Cmds.FindCommand(commandName)
+	if err == nil {
+		p = CommandPath{Managers: managers, Command: cmd}
+		return
+	}
+
+	// fetch group run as a command
+	chain := append([]string{}, commandChain...)
+	if commandName != "" {
+		cmg = cmg.findGroup(commandName)
+		if cmg == nil {
+			p, err = c.findPlugin(commandName, commandChain, err)
+			return
+		}
+		managers = append(managers, cmg)
+		chain = append(chain, commandName)
+	}
+	cmd, err = c.groupCommand(cmg, chain)
 	if err != nil {
 		return
 	}
 
+	p = CommandPath{Managers: managers, Command: cmd}
+	return
+}
+
+// startRun is the method that clears the state of the previous run, see runStarter.
+func (c *CommanderManager) startRun() {
+	if c.Plugins != nil {
+		c.Plugins.clearCache()
+	}
+}
+
+// findPlugin is the method that finds the external plugin of an unknown top-level command.
+// - errNotFound is returned if there is no plugin for the command
+func (c *CommanderManager) findPlugin(commandName string, commandChain []string, errNotFound error) (p CommandPath, err error) {
+	err = errNotFound
+	if c.Plugins == nil || len(commandChain) > 0 {
+		return
+	}
+
+	plugin, ok := c.Plugins.Find(commandName)
+	if !ok {
+		return
+	}
+
+	err = nil
+	p = CommandPath{Managers: []*CommanderManager{c}, Command: c.Plugins.Command(plugin)}
+	return
+}
+
+// groupCommand is the method that returns the command run when a group is run as a command.
+func (c *CommanderManager) groupCommand(cmg *CommanderManager, chain []string) (cmd Command, err error) {
+	switch {
+	// handler of the group
+	case cmg.Handler != nil:
+		cmd = Command{Name: cmg.Name, Description: cmg.Description, Handler: cmg.Handler}
+	// default command of the group
+	case cmg.Default != "":
+		cmd, err = cmg.Cmds.FindCommand(cmg.Default)
+	// help of the group
+	default:
+		cmd = Command{
+			Name: cmg.Name,
+			Description: cmg.Description,
+			Handler: func(i Input) (err error) {
+				w := i.Writer
+				if w == nil {
+					w = os.Stdout
+				}
+				err = c.WriteHelp(w, i.Style, chain...)
+				return
+			},
+		}
+	}
 	return
 }
 
 // FindCommandManager is the method that finds a command manager by name.
 func (c *CommanderManager) FindCommandManager(commandChain ...string) (cm *CommanderManager, err error) {
-	size := len(commandChain)
-	// check if len of commandChain is 0
-	if size == 0 {
-		cm = c
+	managers, err := c.findCommandManagers(commandChain...)
+	if err != nil {
 		return
 	}
 
-	// fetch last command manager
-	var cmg []*CommanderManager = c.CommandManagers
-	for i:=0; i<size; i++ {
+	cm = managers[len(managers)-1]
+	return
+}
+
+// findCommandManagers is the method that finds the command managers of a chain, starting with itself.
+func (c *CommanderManager) findCommandManagers(commandChain ...string) (managers []*CommanderManager, err error) {
+	managers = []*CommanderManager{c}
+
+	// walk the chain
+	current := c
+	for _, name := range commandChain {
 		// exists flag to check if the command manager exists
 		var exists bool
 
 		// iterate over command managers to check if the name exists
-		for j := range cmg {
-			if cmg[j].Name == commandChain[i] {
-				// set that command manager is on right track
+		for _, cmg := range current.CommandManagers {
+			if cmg.Name == name {
 				exists = true
-
-				// last case
-				if i == size-1 {
-					cm = cmg[j]
-					break
-				}
-
-				// set next inner command manager
-				cmg = cmg[j].CommandManagers
-				continue
+				current = cmg
+				break
 			}
 		}
 
 		// check if exists
 		if !exists {
-			cmg = nil
+			managers = nil
 			err = ErrCommandManagerNotFound
 			return
 		}
+		managers = append(managers, current)
 	}
 
 	return
@@ -102,4 +208,29 @@ func (c *CommanderManager) Group(name, description string) (cm Commander) {
 	cm = cmg
 	return
 
-}
\ No newline at end of file
+}
+
+// SetHidden is the method that sets if the command manager is left out of help and completion.
+func (c *CommanderManager) SetHidden(hidden bool) {
+	(*c).Hidden = hidden
+}
+
+// SetDeprecated is the method that sets the deprecation of the command manager.
+func (c *CommanderManager) SetDeprecated(d Deprecated) {
+	(*c).Deprecated = &d
+}
+
+// SetHandler is the method that sets the handler run when the command manager itself is run.
+func (c *CommanderManager) SetHandler(h CommandHandler) {
+	(*c).Handler = h
+}
+
+// SetDefault is the method that sets the command run when the command manager itself is run.
+func (c *CommanderManager) SetDefault(commandName string) {
+	(*c).Default = commandName
+}
+
+// AddFlag is the method that adds a persistent flag to the command manager.
+func (c *CommanderManager) AddFlag(flag Flag) {
+	(*c).Flags = append((*c).Flags, flag)
+}
@@ -1,6 +1,7 @@
 package gocli_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/LNMMusic/gocli"
@@ -229,4 +230,159 @@ func TestCommandManager_Group(t *testing.T) {
 		require.NotNil(t, cm)
 		require.Len(t, cmg.CommandManagers, 1)
 	})
-}
\ No newline at end of file
+}
+
+// TestCommandManager_FindCommandPath is the test for the method FindCommandPath.
+func TestCommandManager_FindCommandPath(t *testing.T) {
+	t.Run("success - case 01: hidden command in a hidden group is found", func(t *testing.T) {
+		// arrange
+		// - command manager
+		cmg := gocli.NewCommanderManager("root", "root command manager")
+		group := cmg.Group("level1", "level 1 command manager")
+		group.(gocli.GroupConfigurer).SetHidden(true)
+		group.AddCommand(gocli.Command{Name: "command", Hidden: true})
+
+		// act
+		p, err := cmg.FindCommandPath("command", "level1")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "command", p.Command.Name)
+		require.Equal(t, []string{"level1"}, p.Chain())
+		require.Len(t, p.Managers, 2)
+		require.True(t, p.Managers[1].Hidden)
+	})
+
+	t.Run("failure - case 01: command not found", func(t *testing.T) {
+		// arrange
+		// - command manager
+		cmg := gocli.NewCommanderManager("root", "root command manager")
+
+		// act
+		p, err := cmg.FindCommandPath("command")
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrCommandHandlerNotFound)
+		require.Equal(t, gocli.CommandPath{}, p)
+	})
+}
+
+// TestCommandManager_SetDeprecated is the test for the method SetDeprecated.
+func TestCommandManager_SetDeprecated(t *testing.T) {
+	t.Run("success - case 01: group is deprecated", func(t *testing.T) {
+		// arrange
+		// - command manager
+		cmg := gocli.NewCommanderManager("root", "root command manager")
+
+		// act
+		cmg.SetDeprecated(gocli.Deprecated{Message: "use v2", Replacement: "v2"})
+
+		// assert
+		require.Equal(t, &gocli.Deprecated{Message: "use v2", Replacement: "v2"}, cmg.Deprecated)
+	})
+}
+
+// TestCommandManager_FindHandler_Group is the test for the method FindHandler when a group is run.
+func TestCommandManager_FindHandler_Group(t *testing.T) {
+	t.Run("success - case 01: group has a handler", func(t *testing.T) {
+		// arrange
+		// - command manager
+		var called string
+		cmg := gocli.NewCommanderManager("app", "app description")
+		db := cmg.Group("db", "database commands")
+		db.(gocli.GroupConfigurer).SetHandler(func(i gocli.Input) (err error) {
+			called = "db"
+			return
+		})
+
+		// act
+		h, err := cmg.FindHandler("db")
+		require.NoError(t, err)
+		err = h(gocli.Input{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "db", called)
+	})
+
+	t.Run("success - case 02: group has a default command", func(t *testing.T) {
+		// arrange
+		// - command manager
+		var called string
+		cmg := gocli.NewCommanderManager("app", "app description")
+		db := cmg.Group("db", "database commands")
+		db.AddCommand(gocli.Command{Name: "status", Handler: func(i gocli.Input) (err error) {
+			called = "status"
+			return
+		}})
+		db.(gocli.GroupConfigurer).SetDefault("status")
+
+		// act
+		p, err := cmg.FindCommandPath("db")
+		require.NoError(t, err)
+		err = p.Command.Handler(gocli.Input{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "status", called)
+		require.Equal(t, []string{"db"}, p.Chain())
+	})
+
+	t.Run("success - case 03: group has neither - help is written", func(t *testing.T) {
+		// arrange
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		db := cmg.Group("db", "database commands")
+		db.AddCommand(gocli.Command{Name: "status", Description: "shows the status"})
+		w := &bytes.Buffer{}
+
+		// act
+		h, err := cmg.FindHandler("db")
+		require.NoError(t, err)
+		err = h(gocli.Input{Writer: w})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app db <command> [flags] [options]\n"+
+			"\ndatabase commands\n"+
+			"\nCommands:\n"+
+			"  status  shows the status\n", w.String())
+	})
+
+	t.Run("success - case 04: root is run with its handler", func(t *testing.T) {
+		// arrange
+		// - command manager
+		var called bool
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.SetHandler(func(i gocli.Input) (err error) {
+			called = true
+			return
+		})
+
+		// act
+		h, err := cmg.FindHandler("")
+		require.NoError(t, err)
+		err = h(gocli.Input{})
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, called)
+	})
+
+	t.Run("failure - case 01: default command does not exist", func(t *testing.T) {
+		// arrange
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		db := cmg.Group("db", "database commands")
+		db.(gocli.GroupConfigurer).SetDefault("status")
+
+		// act
+		h, err := cmg.FindHandler("db")
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrCommandHandlerNotFound)
+		require.Nil(t, h)
+	})
+}
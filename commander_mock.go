@@ -43,3 +43,28 @@ func (m *CommanderMock) Group(name string, description string) (cm Commander) {
 	cm = args.Get(0).(Commander)
 	return
 }
+
+// SetHidden is the method that sets if the group is left out of help and completion.
+func (m *CommanderMock) SetHidden(hidden bool) {
+	m.Called(hidden)
+}
+
+// SetDeprecated is the method that sets the deprecation of the group.
+func (m *CommanderMock) SetDeprecated(d Deprecated) {
+	m.Called(d)
+}
+
+// SetHandler is the method that sets the handler run when the group itself is run.
+func (m *CommanderMock) SetHandler(h CommandHandler) {
+	m.Called(h)
+}
+
+// SetDefault is the method that sets the command run when the group itself is run.
+func (m *CommanderMock) SetDefault(commandName string) {
+	m.Called(commandName)
+}
+
+// AddFlag is the method that adds a persistent flag to the group.
+func (m *CommanderMock) AddFlag(flag Flag) {
+	m.Called(flag)
+}
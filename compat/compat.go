@@ -0,0 +1,451 @@
+// Package compat compares two manifests of a command tree and reports the changes of the CLI surface,
+// flagging the breaking ones so it can gate releases.
+package compat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/LNMMusic/gocli"
+)
+
+var (
+	// ErrBreakingChanges is the error that returns when the new manifest has breaking changes.
+	ErrBreakingChanges = errors.New("breaking changes")
+)
+
+// Severity is the type that represents the impact of a change.
+type Severity string
+
+const (
+	// SeverityBreaking is a change that can break the users of the CLI.
+	SeverityBreaking Severity = "breaking"
+	// SeverityAdditive is a change that does not break the users of the CLI.
+	SeverityAdditive Severity = "additive"
+)
+
+// Change is the struct that represents a change of the CLI surface.
+type Change struct {
+	// Severity is the impact of the change.
+	Severity Severity
+	// Path is the path of the group or command changed, e.g. "db migrate".
+	Path string
+	// Message is the description of the change.
+	Message string
+}
+
+// String is the method that returns the change as a line of the report.
+func (c Change) String() (s string) {
+	s = fmt.Sprintf("[%s] %s: %s", c.Severity, c.Path, c.Message)
+	return
+}
+
+// Report is the struct that represents the changes between two manifests.
+type Report struct {
+	// Changes are the changes, sorted by path.
+	Changes []Change
+}
+
+// Breaking is the method that returns the breaking changes.
+func (r Report) Breaking() (changes []Change) {
+	for _, c := range r.Changes {
+		if c.Severity == SeverityBreaking {
+			changes = append(changes, c)
+		}
+	}
+	return
+}
+
+// Err is the method that returns ErrBreakingChanges if there are breaking changes.
+func (r Report) Err() (err error) {
+	if n := len(r.Breaking()); n > 0 {
+		err = fmt.Errorf("%w: %d found", ErrBreakingChanges, n)
+	}
+	return
+}
+
+// ExitCode is the method that returns the exit code of the comparison: 1 if there are breaking changes.
+func (r Report) ExitCode() (code int) {
+	if r.Err() != nil {
+		code = 1
+	}
+	return
+}
+
+// String is the method that returns the readable report.
+func (r Report) String() (s string) {
+	var b strings.Builder
+	if len(r.Changes) == 0 {
+		b.WriteString("no changes\n")
+	}
+	for _, c := range r.Changes {
+		fmt.Fprintln(&b, c)
+	}
+	fmt.Fprintf(&b, "%d breaking, %d additive\n", len(r.Breaking()), len(r.Changes)-len(r.Breaking()))
+
+	s = b.String()
+	return
+}
+
+// Compare is the function that compares the old and the new manifests.
+// - breaking: removed groups, commands, aliases and flags, flags made required, changed types or defaults, narrowed rules and flag groups
+// - additive: new groups, commands, aliases and optional flags, flags made optional, widened rules and flag groups, deprecations
+// - the persistent flags of the groups are part of the flags of every command under them
+func Compare(old, next gocli.Manifest) (r Report) {
+	var changes []Change
+	add := func(severity Severity, path []string, format string, args ...any) {
+		p := strings.Join(path, " ")
+		if p == "" {
+			p = "(root)"
+		}
+		changes = append(changes, Change{Severity: severity, Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if old.Version != next.Version {
+		add(SeverityBreaking, nil, "manifest version changed from %q to %q", old.Version, next.Version)
+	}
+
+	// groups
+	oldGroups, newGroups := indexGroups(old.Root), indexGroups(next.Root)
+	for _, key := range sortedKeys(oldGroups) {
+		o := oldGroups[key]
+		n, ok := newGroups[key]
+		if !ok {
+			add(SeverityBreaking, o.Path, "group removed")
+			continue
+		}
+		if o.Default != n.Default {
+			add(SeverityBreaking, o.Path, "default command changed from %q to %q", o.Default, n.Default)
+		}
+		if o.Deprecated == nil && n.Deprecated != nil {
+			add(SeverityAdditive, o.Path, "group deprecated")
+		}
+	}
+	for _, key := range sortedKeys(newGroups) {
+		if _, ok := oldGroups[key]; !ok {
+			add(SeverityAdditive, newGroups[key].Path, "group added")
+		}
+	}
+
+	// commands
+	oldCmds, newCmds := indexCommands(old.Root), indexCommands(next.Root)
+	for _, key := range sortedKeys(oldCmds) {
+		o := oldCmds[key]
+		n, ok := newCmds[key]
+		if !ok {
+			add(SeverityBreaking, o.Path, "command removed")
+			continue
+		}
+		compareCommand(o, n, add)
+	}
+	for _, key := range sortedKeys(newCmds) {
+		if _, ok := oldCmds[key]; !ok {
+			add(SeverityAdditive, newCmds[key].Path, "command added")
+		}
+	}
+
+	// sorted by path, keeping the order of the checks within a path
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	r = Report{Changes: changes}
+	return
+}
+
+// compareCommand is the function that compares the old and new versions of a command.
+func compareCommand(o, n gocli.ManifestCommand, add func(severity Severity, path []string, format string, args ...any)) {
+	// args
+	if o.RawArgs && !n.RawArgs {
+		add(SeverityBreaking, o.Path, "command no longer accepts raw args")
+	}
+
+	// aliases
+	for _, alias := range o.Aliases {
+		if !contains(n.Aliases, alias) {
+			add(SeverityBreaking, o.Path, "alias %q removed", alias)
+		}
+	}
+	for _, alias := range n.Aliases {
+		if !contains(o.Aliases, alias) {
+			add(SeverityAdditive, o.Path, "alias %q added", alias)
+		}
+	}
+
+	// deprecation
+	if o.Deprecated == nil && n.Deprecated != nil {
+		add(SeverityAdditive, o.Path, "command deprecated")
+	}
+
+	// flags
+	oldFlags, newFlags := indexFlags(o.Flags), indexFlags(n.Flags)
+	var removed, added []string
+	for _, f := range o.Flags {
+		nf, ok := newFlags[f.Name]
+		if !ok {
+			removed = append(removed, f.Name)
+			continue
+		}
+		if !f.Required && nf.Required {
+			add(SeverityBreaking, o.Path, "flag --%s changed from optional to required", f.Name)
+		}
+		if f.Required && !nf.Required {
+			add(SeverityAdditive, o.Path, "flag --%s changed from required to optional", f.Name)
+		}
+		if f.Type != nf.Type {
+			add(SeverityBreaking, o.Path, "flag --%s changed type from %s to %s", f.Name, f.Type, nf.Type)
+		}
+		if f.Default != nf.Default {
+			add(SeverityBreaking, o.Path, "flag --%s changed default from %q to %q", f.Name, f.Default, nf.Default)
+		}
+		compareRules(o.Path, f, nf, add)
+		if f.Deprecated == nil && nf.Deprecated != nil {
+			add(SeverityAdditive, o.Path, "flag --%s deprecated", f.Name)
+		}
+	}
+	for _, f := range n.Flags {
+		if _, ok := oldFlags[f.Name]; ok {
+			continue
+		}
+		added = append(added, f.Name)
+		if f.Required {
+			add(SeverityBreaking, o.Path, "required flag --%s added", f.Name)
+			continue
+		}
+		add(SeverityAdditive, o.Path, "flag --%s added", f.Name)
+	}
+	for _, name := range removed {
+		// a removed flag with a new one in its place is probably a rename
+		if len(removed) == 1 && len(added) == 1 {
+			add(SeverityBreaking, o.Path, "flag --%s removed (renamed to --%s?)", name, added[0])
+			continue
+		}
+		add(SeverityBreaking, o.Path, "flag --%s removed", name)
+	}
+
+	// flag groups
+	compareFlagGroups(o.Path, o.FlagGroups, n.FlagGroups, add)
+}
+
+// compareFlagGroups is the function that compares the flag groups of the old and new versions of a command.
+// - a group changed is matched with the old group of the same kind that shares a flag with it
+// - a group added or narrowed is breaking, since flags accepted together before are rejected
+// - a group removed or widened is additive
+func compareFlagGroups(path []string, o, n []gocli.FlagGroup, add func(severity Severity, path []string, format string, args ...any)) {
+	matched := make(map[int]bool)
+	for _, g := range n {
+		i, ok := matchFlagGroup(o, g)
+		if !ok {
+			add(SeverityBreaking, path, "flag group %s (%s) added", g.Names(), g.Description())
+			continue
+		}
+		matched[i] = true
+		if sameFlags(o[i].Flags, g.Flags) {
+			continue
+		}
+
+		// - exclusive and together groups are narrowed by more flags, at least one groups by fewer flags,
+		// exactly one groups by any change
+		og := o[i]
+		var narrowed bool
+		switch g.Kind {
+		case gocli.FlagGroupExclusive, gocli.FlagGroupTogether:
+			narrowed = !subset(g.Flags, og.Flags)
+		case gocli.FlagGroupAtLeastOne:
+			narrowed = !subset(og.Flags, g.Flags)
+		default:
+			narrowed = true
+		}
+		if narrowed {
+			add(SeverityBreaking, path, "flag group %s (%s) narrowed to %s", og.Names(), og.Description(), g.Names())
+			continue
+		}
+		add(SeverityAdditive, path, "flag group %s (%s) widened to %s", og.Names(), og.Description(), g.Names())
+	}
+	for i, g := range o {
+		if !matched[i] {
+			add(SeverityAdditive, path, "flag group %s (%s) removed", g.Names(), g.Description())
+		}
+	}
+}
+
+// matchFlagGroup is the function that returns the index of the old group matching a new group.
+// - the same group wins, then the first group of the same kind sharing a flag
+func matchFlagGroup(groups []gocli.FlagGroup, g gocli.FlagGroup) (index int, ok bool) {
+	for i, og := range groups {
+		if og.Kind == g.Kind && sameFlags(og.Flags, g.Flags) {
+			index, ok = i, true
+			return
+		}
+	}
+	for i, og := range groups {
+		if og.Kind != g.Kind {
+			continue
+		}
+		for _, name := range og.Flags {
+			if contains(g.Flags, name) {
+				index, ok = i, true
+				return
+			}
+		}
+	}
+	return
+}
+
+// sameFlags is the function that checks if two lists have the same flags, in any order.
+func sameFlags(a, b []string) (ok bool) {
+	ok = subset(a, b) && subset(b, a)
+	return
+}
+
+// subset is the function that checks if all the items of a list are in another list.
+func subset(list, of []string) (ok bool) {
+	for _, item := range list {
+		if !contains(of, item) {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// compareRules is the function that compares the validation rules of the old and new versions of a flag.
+// - a rule added or narrowed is breaking, since values accepted before are rejected
+// - a rule removed or widened is additive
+func compareRules(path []string, o, n gocli.ManifestFlag, add func(severity Severity, path []string, format string, args ...any)) {
+	// enum
+	switch {
+	case len(o.Enum) == 0 && len(n.Enum) > 0:
+		add(SeverityBreaking, path, "flag --%s restricted to one of: %s", o.Name, strings.Join(n.Enum, ", "))
+	case len(o.Enum) > 0 && len(n.Enum) == 0:
+		add(SeverityAdditive, path, "flag --%s no longer restricted to one of: %s", o.Name, strings.Join(o.Enum, ", "))
+	default:
+		for _, value := range o.Enum {
+			if !contains(n.Enum, value) {
+				add(SeverityBreaking, path, "flag --%s no longer accepts %q", o.Name, value)
+			}
+		}
+		for _, value := range n.Enum {
+			if !contains(o.Enum, value) {
+				add(SeverityAdditive, path, "flag --%s accepts %q", o.Name, value)
+			}
+		}
+	}
+
+	// min
+	switch {
+	case o.Min == nil && n.Min != nil:
+		add(SeverityBreaking, path, "flag --%s minimum %v added", o.Name, *n.Min)
+	case o.Min != nil && n.Min == nil:
+		add(SeverityAdditive, path, "flag --%s minimum %v removed", o.Name, *o.Min)
+	case o.Min != nil && *n.Min > *o.Min:
+		add(SeverityBreaking, path, "flag --%s minimum raised from %v to %v", o.Name, *o.Min, *n.Min)
+	case o.Min != nil && *n.Min < *o.Min:
+		add(SeverityAdditive, path, "flag --%s minimum lowered from %v to %v", o.Name, *o.Min, *n.Min)
+	}
+
+	// max
+	switch {
+	case o.Max == nil && n.Max != nil:
+		add(SeverityBreaking, path, "flag --%s maximum %v added", o.Name, *n.Max)
+	case o.Max != nil && n.Max == nil:
+		add(SeverityAdditive, path, "flag --%s maximum %v removed", o.Name, *o.Max)
+	case o.Max != nil && *n.Max < *o.Max:
+		add(SeverityBreaking, path, "flag --%s maximum lowered from %v to %v", o.Name, *o.Max, *n.Max)
+	case o.Max != nil && *n.Max > *o.Max:
+		add(SeverityAdditive, path, "flag --%s maximum raised from %v to %v", o.Name, *o.Max, *n.Max)
+	}
+
+	// pattern: a changed pattern may reject values accepted before
+	switch {
+	case o.Pattern == "" && n.Pattern != "":
+		add(SeverityBreaking, path, "flag --%s pattern %q added", o.Name, n.Pattern)
+	case o.Pattern != "" && n.Pattern == "":
+		add(SeverityAdditive, path, "flag --%s pattern %q removed", o.Name, o.Pattern)
+	case o.Pattern != n.Pattern:
+		add(SeverityBreaking, path, "flag --%s pattern changed from %q to %q", o.Name, o.Pattern, n.Pattern)
+	}
+
+	// exists: a path that had not to exist may not
+	switch {
+	case o.Exists == "" && n.Exists != "":
+		add(SeverityBreaking, path, "flag --%s must exist as a %s", o.Name, n.Exists)
+	case o.Exists != "" && n.Exists == "":
+		add(SeverityAdditive, path, "flag --%s no longer must exist as a %s", o.Name, o.Exists)
+	case o.Exists != n.Exists:
+		add(SeverityBreaking, path, "flag --%s must exist as a %s instead of a %s", o.Name, n.Exists, o.Exists)
+	}
+}
+
+// indexGroups is the function that indexes the groups of a tree by path, root excluded.
+func indexGroups(root gocli.ManifestGroup) (index map[string]gocli.ManifestGroup) {
+	index = make(map[string]gocli.ManifestGroup)
+	var walk func(g gocli.ManifestGroup)
+	walk = func(g gocli.ManifestGroup) {
+		for _, sub := range g.Groups {
+			index[strings.Join(sub.Path, " ")] = sub
+			walk(sub)
+		}
+	}
+	walk(root)
+	return
+}
+
+// indexCommands is the function that indexes the commands of a tree by path.
+// - the flags of a command include the persistent flags of its groups, a flag of the command shadows them
+func indexCommands(root gocli.ManifestGroup) (index map[string]gocli.ManifestCommand) {
+	index = make(map[string]gocli.ManifestCommand)
+	var walk func(g gocli.ManifestGroup, inherited []gocli.ManifestFlag)
+	walk = func(g gocli.ManifestGroup, inherited []gocli.ManifestFlag) {
+		inherited = mergeFlags(g.Flags, inherited)
+		for _, cmd := range g.Commands {
+			cmd.Flags = mergeFlags(cmd.Flags, inherited)
+			index[strings.Join(cmd.Path, " ")] = cmd
+		}
+		for _, sub := range g.Groups {
+			walk(sub, inherited)
+		}
+	}
+	walk(root, nil)
+	return
+}
+
+// mergeFlags is the function that returns the flags followed by the inherited flags they do not shadow.
+func mergeFlags(flags, inherited []gocli.ManifestFlag) (merged []gocli.ManifestFlag) {
+	merged = append(merged, flags...)
+	own := indexFlags(flags)
+	for _, f := range inherited {
+		if _, ok := own[f.Name]; !ok {
+			merged = append(merged, f)
+		}
+	}
+	return
+}
+
+// indexFlags is the function that indexes flags by name.
+func indexFlags(flags []gocli.ManifestFlag) (index map[string]gocli.ManifestFlag) {
+	index = make(map[string]gocli.ManifestFlag)
+	for _, f := range flags {
+		index[f.Name] = f
+	}
+	return
+}
+
+// sortedKeys is the function that returns the keys of a map sorted.
+func sortedKeys[T any](m map[string]T) (keys []string) {
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// contains is the function that checks if a list contains a value.
+func contains(list []string, value string) (ok bool) {
+	for _, item := range list {
+		if item == value {
+			ok = true
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,287 @@
+package compat_test
+
+import (
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/gocli/compat"
+	"github.com/stretchr/testify/require"
+)
+
+// newManifest is the function that returns the manifest of a tree built by fn.
+func newManifest(fn func(cm *gocli.CommanderManager)) (m gocli.Manifest) {
+	cm := gocli.NewCommanderManager("app", "app description")
+	fn(cm)
+	m = gocli.NewManifest(cm)
+	return
+}
+
+// TestCompare is the test for the function Compare.
+func TestCompare(t *testing.T) {
+	// base is the tree of the old manifest
+	base := func(cm *gocli.CommanderManager) {
+		cm.AddCommand(gocli.Command{Name: "version"})
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{
+			Name:    "migrate",
+			Aliases: []string{"m"},
+			Flags: gocli.Flags{
+				{Name: "env", Default: "dev"},
+				{Name: "steps", Type: gocli.FlagTypeInt},
+				{Name: "dry"},
+			},
+		})
+	}
+
+	t.Run("success - case 01: same manifest has no changes", func(t *testing.T) {
+		// act
+		r := compat.Compare(newManifest(base), newManifest(base))
+
+		// assert
+		require.Empty(t, r.Changes)
+		require.NoError(t, r.Err())
+		require.Equal(t, 0, r.ExitCode())
+		require.Equal(t, "no changes\n0 breaking, 0 additive\n", r.String())
+	})
+
+	t.Run("success - case 02: additive changes pass", func(t *testing.T) {
+		// arrange
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			base(cm)
+			cm.AddCommand(gocli.Command{Name: "status"})
+			cm.CommandManagers[0].Cmds[0].Aliases = append(cm.CommandManagers[0].Cmds[0].Aliases, "up")
+			cm.CommandManagers[0].Cmds[0].Flags = append(cm.CommandManagers[0].Cmds[0].Flags, gocli.Flag{Name: "verbose"})
+			cm.Group("cache", "cache commands")
+		})
+
+		// act
+		r := compat.Compare(newManifest(base), next)
+
+		// assert
+		require.NoError(t, r.Err())
+		require.Equal(t, 0, r.ExitCode())
+		require.Equal(t, "[additive] cache: group added\n"+
+			"[additive] db migrate: alias \"up\" added\n"+
+			"[additive] db migrate: flag --verbose added\n"+
+			"[additive] status: command added\n"+
+			"0 breaking, 4 additive\n", r.String())
+	})
+
+	t.Run("success - case 03: flag moved from the command to its group", func(t *testing.T) {
+		// arrange
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			base(cm)
+			db := cm.CommandManagers[0]
+			db.Flags = gocli.Flags{db.Cmds[0].Flags[0]}
+			db.Cmds[0].Flags = db.Cmds[0].Flags[1:]
+		})
+
+		// act
+		r := compat.Compare(newManifest(base), next)
+
+		// assert
+		require.Empty(t, r.Changes)
+	})
+
+	t.Run("success - case 04: widened rules are additive", func(t *testing.T) {
+		// arrange
+		min, max := 1.0, 10.0
+		lower, higher := 0.0, 20.0
+		old := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "env", Enum: []string{"dev", "prod"}},
+				{Name: "replicas", Type: gocli.FlagTypeInt, Min: &min, Max: &max},
+				{Name: "tag", Pattern: `^v\d+$`},
+			}})
+		})
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "env", Enum: []string{"dev", "prod", "staging"}},
+				{Name: "replicas", Type: gocli.FlagTypeInt, Min: &lower, Max: &higher},
+				{Name: "tag"},
+			}})
+		})
+
+		// act
+		r := compat.Compare(old, next)
+
+		// assert
+		require.NoError(t, r.Err())
+		require.Equal(t, "[additive] deploy: flag --env accepts \"staging\"\n"+
+			"[additive] deploy: flag --replicas minimum lowered from 1 to 0\n"+
+			"[additive] deploy: flag --replicas maximum raised from 10 to 20\n"+
+			"[additive] deploy: flag --tag pattern \"^v\\\\d+$\" removed\n"+
+			"0 breaking, 4 additive\n", r.String())
+	})
+
+	t.Run("success - case 05: widened flag groups and removed exists rules are additive", func(t *testing.T) {
+		// arrange
+		old := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "file", Exists: gocli.FlagExistsFile}, {Name: "stdin"}, {Name: "url"}, {Name: "user"}, {Name: "password"},
+			}, FlagGroups: []gocli.FlagGroup{
+				{Kind: gocli.FlagGroupExclusive, Flags: []string{"file", "stdin", "url"}},
+				{Kind: gocli.FlagGroupAtLeastOne, Flags: []string{"file", "stdin"}},
+				{Kind: gocli.FlagGroupTogether, Flags: []string{"user", "password"}},
+			}})
+		})
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "file"}, {Name: "stdin"}, {Name: "url"}, {Name: "user"}, {Name: "password"},
+			}, FlagGroups: []gocli.FlagGroup{
+				{Kind: gocli.FlagGroupExclusive, Flags: []string{"file", "stdin"}},
+				{Kind: gocli.FlagGroupAtLeastOne, Flags: []string{"file", "stdin", "url"}},
+			}})
+		})
+
+		// act
+		r := compat.Compare(old, next)
+
+		// assert
+		require.NoError(t, r.Err())
+		require.Equal(t, "[additive] deploy: flag --file no longer must exist as a file\n"+
+			"[additive] deploy: flag group --file, --stdin, --url (mutually exclusive) widened to --file, --stdin\n"+
+			"[additive] deploy: flag group --file, --stdin (at least one required) widened to --file, --stdin, --url\n"+
+			"[additive] deploy: flag group --user, --password (required together) removed\n"+
+			"0 breaking, 4 additive\n", r.String())
+	})
+
+	t.Run("failure - case 01: breaking changes are flagged", func(t *testing.T) {
+		// arrange
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			db := cm.Group("db", "database commands")
+			db.AddCommand(gocli.Command{
+				Name: "migrate",
+				Flags: gocli.Flags{
+					{Name: "env", Default: "prod", Required: true},
+					{Name: "steps", Type: gocli.FlagTypeString},
+					{Name: "dry-run"},
+				},
+			})
+		})
+
+		// act
+		r := compat.Compare(newManifest(base), next)
+
+		// assert
+		require.Error(t, r.Err())
+		require.ErrorIs(t, r.Err(), compat.ErrBreakingChanges)
+		require.Equal(t, 1, r.ExitCode())
+		require.Equal(t, "[breaking] db migrate: alias \"m\" removed\n"+
+			"[breaking] db migrate: flag --env changed from optional to required\n"+
+			"[breaking] db migrate: flag --env changed default from \"dev\" to \"prod\"\n"+
+			"[breaking] db migrate: flag --steps changed type from int to string\n"+
+			"[additive] db migrate: flag --dry-run added\n"+
+			"[breaking] db migrate: flag --dry removed (renamed to --dry-run?)\n"+
+			"[breaking] version: command removed\n"+
+			"6 breaking, 1 additive\n", r.String())
+	})
+
+	t.Run("failure - case 02: new required flag and removed group", func(t *testing.T) {
+		// arrange
+		old := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy"})
+			cm.Group("cache", "cache commands")
+		})
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{{Name: "env", Required: true}}})
+		})
+
+		// act
+		r := compat.Compare(old, next)
+
+		// assert
+		require.Len(t, r.Breaking(), 2)
+		require.Equal(t, "[breaking] cache: group removed\n"+
+			"[breaking] deploy: required flag --env added\n"+
+			"2 breaking, 0 additive\n", r.String())
+	})
+
+	t.Run("failure - case 03: persistent flags of the groups are compared", func(t *testing.T) {
+		// arrange
+		old := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddFlag(gocli.Flag{Name: "profile"})
+			db := cm.Group("db", "database commands")
+			db.(gocli.GroupConfigurer).AddFlag(gocli.Flag{Name: "region", Default: "eu"})
+			db.AddCommand(gocli.Command{Name: "migrate"})
+		})
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddFlag(gocli.Flag{Name: "profile", Required: true})
+			db := cm.Group("db", "database commands")
+			db.AddCommand(gocli.Command{Name: "migrate"})
+		})
+
+		// act
+		r := compat.Compare(old, next)
+
+		// assert
+		require.Equal(t, "[breaking] db migrate: flag --profile changed from optional to required\n"+
+			"[breaking] db migrate: flag --region removed\n"+
+			"2 breaking, 0 additive\n", r.String())
+	})
+
+	t.Run("failure - case 04: new and narrowed rules are breaking", func(t *testing.T) {
+		// arrange
+		min, max := 1.0, 10.0
+		higher, lower := 2.0, 5.0
+		old := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "env", Enum: []string{"dev", "prod"}},
+				{Name: "replicas", Type: gocli.FlagTypeInt, Min: &min, Max: &max},
+				{Name: "tag"},
+				{Name: "zone"},
+			}})
+		})
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "env", Enum: []string{"prod"}},
+				{Name: "replicas", Type: gocli.FlagTypeInt, Min: &higher, Max: &lower},
+				{Name: "tag", Pattern: `^v\d+$`},
+				{Name: "zone", Enum: []string{"a", "b"}},
+			}})
+		})
+
+		// act
+		r := compat.Compare(old, next)
+
+		// assert
+		require.Equal(t, "[breaking] deploy: flag --env no longer accepts \"dev\"\n"+
+			"[breaking] deploy: flag --replicas minimum raised from 1 to 2\n"+
+			"[breaking] deploy: flag --replicas maximum lowered from 10 to 5\n"+
+			"[breaking] deploy: flag --tag pattern \"^v\\\\d+$\" added\n"+
+			"[breaking] deploy: flag --zone restricted to one of: a, b\n"+
+			"5 breaking, 0 additive\n", r.String())
+	})
+	t.Run("failure - case 05: new and narrowed flag groups and exists rules are breaking", func(t *testing.T) {
+		// arrange
+		old := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "file"}, {Name: "dir", Exists: gocli.FlagExistsFile}, {Name: "stdin"}, {Name: "url"}, {Name: "user"}, {Name: "password"},
+			}, FlagGroups: []gocli.FlagGroup{
+				{Kind: gocli.FlagGroupExclusive, Flags: []string{"file", "stdin"}},
+				{Kind: gocli.FlagGroupAtLeastOne, Flags: []string{"file", "stdin", "url"}},
+			}})
+		})
+		next := newManifest(func(cm *gocli.CommanderManager) {
+			cm.AddCommand(gocli.Command{Name: "deploy", Flags: gocli.Flags{
+				{Name: "file", Exists: gocli.FlagExistsFile}, {Name: "dir", Exists: gocli.FlagExistsDir}, {Name: "stdin"}, {Name: "url"}, {Name: "user"}, {Name: "password"},
+			}, FlagGroups: []gocli.FlagGroup{
+				{Kind: gocli.FlagGroupExclusive, Flags: []string{"file", "stdin", "url"}},
+				{Kind: gocli.FlagGroupAtLeastOne, Flags: []string{"file", "stdin"}},
+				{Kind: gocli.FlagGroupTogether, Flags: []string{"user", "password"}},
+			}})
+		})
+
+		// act
+		r := compat.Compare(old, next)
+
+		// assert
+		require.ErrorIs(t, r.Err(), compat.ErrBreakingChanges)
+		require.Equal(t, "[breaking] deploy: flag --file must exist as a file\n"+
+			"[breaking] deploy: flag --dir must exist as a dir instead of a file\n"+
+			"[breaking] deploy: flag group --file, --stdin (mutually exclusive) narrowed to --file, --stdin, --url\n"+
+			"[breaking] deploy: flag group --file, --stdin, --url (at least one required) narrowed to --file, --stdin\n"+
+			"[breaking] deploy: flag group --user, --password (required together) added\n"+
+			"5 breaking, 0 additive\n", r.String())
+	})
+}
@@ -0,0 +1,148 @@
+package gocli
+
+import (
+	"regexp"
+	"strings"
+)
+
+// patternOption is the regexp pattern of an option, which unlike a flag has no value.
+var patternOption = regexp.MustCompile(`^-[A-Z0-9]+$`)
+
+// Completer is the interface that wraps the method to complete the command line.
+// It is optional for a Commander, the CLI only answers the __complete command if implemented.
+type Completer interface {
+	// Complete is the method that returns the candidates for the last arg.
+	// - args are the args typed so far, the last one is the partial arg being completed (it may be empty)
+	Complete(args ...string) (candidates []string)
+}
+
+// Complete is the method that returns the candidates for the last arg.
+// - groups and commands are completed until a command is found, then its flags
+// - user aliases are completed as the first arg, and expanded when typed
+// - hidden groups, commands and flags are left out
+func (c *CommanderManager) Complete(args ...string) (candidates []string) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	words, partial := args[:len(args)-1], args[len(args)-1]
+
+	// a user alias typed first is completed as the args it expands to
+	if len(words) > 0 {
+		if a, ok := c.FindAlias(words[0]); ok && !a.Shadowed && !a.Macro() {
+			if aliasArgs, err := a.Args(); err == nil {
+				words = append(aliasArgs, words[1:]...)
+			}
+		}
+	}
+
+	// walk the words typed so far
+	current := c
+	managers := []*CommanderManager{c}
+	var cmd *Command
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		// flags and options
+		if strings.HasPrefix(word, "-") {
+			if patternOption.MatchString(word) {
+				continue
+			}
+			// the value of the flag is being completed
+			// - the allowed values of the flag are the candidates
+			if i == len(words)-1 {
+				if f, ok := completeFlags(cmd, managers).Find(strings.TrimLeft(word, "-")); ok && !f.Hidden {
+					for _, value := range f.Enum {
+						if strings.HasPrefix(value, partial) {
+							candidates = append(candidates, value)
+						}
+					}
+					// - or the candidates of the custom type of the flag
+					if f.Value != nil && len(f.Enum) == 0 {
+						if vc, ok := f.Value().(ValueCompleter); ok {
+							candidates = vc.Complete(partial)
+						}
+					}
+				}
+				return
+			}
+			i++
+			continue
+		}
+
+		// the chain ends in a command
+		if cmd != nil {
+			continue
+		}
+
+		// group
+		if sub := current.findGroup(word); sub != nil {
+			current = sub
+			managers = append(managers, sub)
+			continue
+		}
+		// command
+		if found, err := current.Cmds.FindCommand(word); err == nil {
+			cmd = &found
+			continue
+		}
+		// unknown word
+		return
+	}
+
+	// flags
+	// - the persistent flags of the groups are completed in any position of the chain
+	if strings.HasPrefix(partial, "-") {
+		for _, f := range completeFlags(cmd, managers) {
+			if !f.Hidden && strings.HasPrefix("--"+f.Name, partial) {
+				candidates = append(candidates, "--"+f.Name)
+			}
+		}
+		return
+	}
+
+	// commands and groups
+	if cmd != nil {
+		return
+	}
+	for _, cm := range current.Cmds {
+		if !cm.Hidden && strings.HasPrefix(cm.Name, partial) {
+			candidates = append(candidates, cm.Name)
+		}
+	}
+	for _, sub := range current.CommandManagers {
+		if !sub.Hidden && strings.HasPrefix(sub.Name, partial) {
+			candidates = append(candidates, sub.Name)
+		}
+	}
+	// - user aliases, as the first arg
+	if len(words) == 0 && c.Aliases != nil {
+		aliases, _ := c.Aliases.List()
+		for _, a := range aliases {
+			if !c.shadows(a.Name) && strings.HasPrefix(a.Name, partial) {
+				candidates = append(candidates, a.Name)
+			}
+		}
+	}
+	return
+}
+
+// completeFlags is the function that returns the flags accepted so far: the ones of the command, if any, and the persistent ones.
+func completeFlags(cmd *Command, managers []*CommanderManager) (flags Flags) {
+	var own Flags
+	if cmd != nil {
+		own = cmd.Flags
+	}
+	flags = inheritFlags(own, managers)
+	return
+}
+
+// findGroup is the method that finds a direct command manager by name.
+func (c *CommanderManager) findGroup(name string) (cm *CommanderManager) {
+	for _, sub := range c.CommandManagers {
+		if sub.Name == name {
+			cm = sub
+			return
+		}
+	}
+	return
+}
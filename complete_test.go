@@ -0,0 +1,116 @@
+package gocli_test
+
+import (
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommanderManager_Complete is the test for the method Complete.
+func TestCommanderManager_Complete(t *testing.T) {
+	// cmg is the command manager used in the tests
+	cmg := &gocli.CommanderManager{
+		Name: "app",
+		Flags: gocli.Flags{
+			{Name: "profile", Enum: []string{"dev", "prod"}},
+		},
+		Cmds: gocli.Commands{
+			{Name: "deploy"},
+			{Name: "debug", Hidden: true},
+		},
+		CommandManagers: []*gocli.CommanderManager{
+			{
+				Name: "db",
+				Cmds: gocli.Commands{
+					{Name: "migrate", Flags: gocli.Flags{
+						{Name: "dry-run"},
+						{Name: "driver", Enum: []string{"postgres", "mysql", "sqlite"}},
+						{Name: "legacy", Hidden: true},
+					}},
+				},
+			},
+			{Name: "internal", Hidden: true},
+		},
+	}
+
+	t.Run("success - case 01: commands and groups of the root", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("d")
+
+		// assert
+		require.Equal(t, []string{"deploy", "db"}, candidates)
+	})
+
+	t.Run("success - case 02: commands of a group", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("db", "")
+
+		// assert
+		require.Equal(t, []string{"migrate"}, candidates)
+	})
+
+	t.Run("success - case 03: flags of a command", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("db", "migrate", "--dry-run", "true", "-O1", "--")
+
+		// assert
+		require.Equal(t, []string{"--dry-run", "--driver", "--profile"}, candidates)
+	})
+
+	t.Run("success - case 04: allowed values of a flag", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("db", "migrate", "--driver", "s")
+
+		// assert
+		require.Equal(t, []string{"sqlite"}, candidates)
+	})
+
+	t.Run("success - case 05: hidden items are left out", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("i")
+
+		// assert
+		require.Nil(t, candidates)
+	})
+
+	t.Run("success - case 06: unknown command has no candidates", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("unknown", "")
+
+		// assert
+		require.Nil(t, candidates)
+	})
+
+	t.Run("success - case 07: persistent flags of the groups", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("db", "--")
+
+		// assert
+		require.Equal(t, []string{"--profile"}, candidates)
+	})
+
+	t.Run("success - case 08: allowed values of a persistent flag within the chain", func(t *testing.T) {
+		// act
+		candidates := cmg.Complete("--profile", "p")
+
+		// assert
+		require.Equal(t, []string{"prod"}, candidates)
+	})
+
+	t.Run("success - case 09: values of a flag with a custom type", func(t *testing.T) {
+		// arrange
+		cmg := &gocli.CommanderManager{
+			Name: "app",
+			Cmds: gocli.Commands{
+				{Name: "upload", Flags: gocli.Flags{{Name: "limit", Value: gocli.NewByteSize}}},
+			},
+		}
+
+		// act
+		candidates := cmg.Complete("upload", "--limit", "10g")
+
+		// assert
+		require.Equal(t, []string{"10GB", "10GiB"}, candidates)
+	})
+}
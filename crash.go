@@ -0,0 +1,114 @@
+package gocli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExitCodePanic is the exit code of a run whose handler panicked.
+// - it is EX_SOFTWARE of sysexits.h, an internal software error
+const ExitCodePanic = 70
+
+// PanicError is the struct that represents a panic of a command handler, recovered by the CLI.
+type PanicError struct {
+	// Value is the value given to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+	// Report is the path of the crash report, empty if none was written.
+	Report string
+}
+
+// Error is the method that returns a short message of the error, without the stack trace.
+func (e *PanicError) Error() (msg string) {
+	msg = fmt.Sprintf("unexpected internal error (panic: %v)", e.Value)
+	if e.Report != "" {
+		msg += fmt.Sprintf(", a crash report was written to %s", e.Report)
+	}
+	return
+}
+
+// callHandler is the method that runs a command handler, recovering its panic as a PanicError.
+// - a crash report is written if the CLI has a crash dir
+func (c CLI) callHandler(handler CommandHandler, input Input) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		panicErr := &PanicError{Value: v, Stack: debug.Stack()}
+		if c.CrashDir != "" {
+			path, rerr := c.writeCrashReport(panicErr, input)
+			if rerr != nil {
+				input.Logger.Warn("crash report not written", "error", rerr)
+			}
+			panicErr.Report = path
+		}
+		err = panicErr
+	}()
+
+	err = handler(input)
+	return
+}
+
+// writeCrashReport is the method that writes the crash report of a panic to a new file in the crash dir.
+// - the values of the flags are redacted, only their names are written
+func (c CLI) writeCrashReport(panicErr *PanicError, input Input) (path string, err error) {
+	err = os.MkdirAll(c.CrashDir, 0o700)
+	if err != nil {
+		return
+	}
+
+	// flags
+	names := make([]string, 0, len(input.Flags))
+	for name := range input.Flags {
+		names = append(names, "--"+name+"=***")
+	}
+	sort.Strings(names)
+
+	// report
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "crash report\n\n")
+	fmt.Fprintf(&b, "time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "app version: %s\n", c.Version)
+	fmt.Fprintf(&b, "gocli version: %s\n", gocliVersion())
+	fmt.Fprintf(&b, "go version: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "os: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "command: %s\n", commandAttr(input.CommandInput).Value.String())
+	fmt.Fprintf(&b, "flags: %s\n", strings.Join(names, " "))
+	fmt.Fprintf(&b, "panic: %v\n\n", panicErr.Value)
+	b.Write(panicErr.Stack)
+
+	path = filepath.Join(c.CrashDir, fmt.Sprintf("crash-%s-%s.txt", now.Format("20060102-150405"), randomHex(4)))
+	err = os.WriteFile(path, []byte(b.String()), 0o600)
+	if err != nil {
+		path = ""
+	}
+	return
+}
+
+// gocliVersion is the function that returns the version of the gocli module the binary was built with.
+// - "(devel)" if it is not known, e.g. in its own tests or in a build without module information
+func gocliVersion() (v string) {
+	v = "(devel)"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/LNMMusic/gocli" {
+			v = dep.Version
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,79 @@
+package gocli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_Run_Panic is the test for the panic recovery of the method Run.
+func TestCLI_Run_Panic(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func() (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{
+			Name: "migrate",
+			Handler: func(i gocli.Input) (err error) {
+				var m map[string]int
+				m["steps"] = 1
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Version = "1.2.0"
+		cli.ErrWriter = &bytes.Buffer{}
+		return
+	}
+
+	t.Run("success - case 01: panic is returned as an error with its own exit code", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--token", "s3cr3t"}
+		cli := newCLI()
+
+		// act
+		err := cli.Run()
+
+		// assert
+		var panicErr *gocli.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, gocli.ExitCodePanic, gocli.ExitCode(err))
+		require.Empty(t, panicErr.Report)
+		require.Contains(t, string(panicErr.Stack), "crash_test.go")
+		require.EqualError(t, err, "unexpected internal error (panic: assignment to entry in nil map)")
+	})
+
+	t.Run("success - case 02: crash report is written", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--token", "s3cr3t"}
+		cli := newCLI()
+		cli.CrashDir = filepath.Join(t.TempDir(), "crashes")
+
+		// act
+		err := cli.Run()
+
+		// assert
+		var panicErr *gocli.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, cli.CrashDir, filepath.Dir(panicErr.Report))
+		require.Contains(t, err.Error(), "a crash report was written to "+panicErr.Report)
+
+		report, rerr := os.ReadFile(panicErr.Report)
+		require.NoError(t, rerr)
+		require.Contains(t, string(report), "app version: 1.2.0\n")
+		require.Contains(t, string(report), "gocli version: ")
+		require.Contains(t, string(report), "go version: "+runtime.Version()+"\n")
+		require.Contains(t, string(report), "os: "+runtime.GOOS+"/"+runtime.GOARCH+"\n")
+		require.Contains(t, string(report), "command: db migrate\n")
+		require.Contains(t, string(report), "flags: --token=***\n")
+		require.Contains(t, string(report), "panic: assignment to entry in nil map\n")
+		require.Contains(t, string(report), "crash_test.go")
+		require.NotContains(t, string(report), "s3cr3t")
+	})
+}
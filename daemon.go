@@ -0,0 +1,573 @@
+package gocli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LNMMusic/optional"
+)
+
+var (
+	// ErrDaemonRunning is the error that returns when a daemon is already listening on the socket.
+	ErrDaemonRunning = errors.New("daemon already running")
+	// ErrDaemonNotStarted is the error that returns when the daemon could not be started.
+	ErrDaemonNotStarted = errors.New("daemon not started")
+	// ErrDaemonVersionMismatch is the error that returns when the daemon is from another version.
+	ErrDaemonVersionMismatch = errors.New("daemon version mismatch")
+	// ErrDaemonDisconnected is the error that returns when the connection to the daemon is lost.
+	ErrDaemonDisconnected = errors.New("daemon disconnected")
+	// ErrDaemonUnsafe is the error that returns when the socket, its dir or the process on the other side is not the user's.
+	ErrDaemonUnsafe = errors.New("unsafe daemon socket")
+)
+
+// envDaemonSocket is the environment variable that makes the process run as the daemon listening on its socket.
+const envDaemonSocket = "GOCLI_DAEMON_SOCKET"
+
+// ConfigDaemon is the struct that wraps the configuration of the daemon.
+type ConfigDaemon struct {
+	// Socket is the path of the Unix socket of the daemon.
+	// - default: $XDG_RUNTIME_DIR/<app>.sock, or <tmp>/<app>-<uid>/daemon.sock if it is not set
+	// - the dir of the socket must be owned by the user and private to it, it is created if it does not exist
+	Socket string
+	// IdleTimeout is the time without requests after which the daemon shuts down.
+	// - default: 10m
+	IdleTimeout time.Duration
+	// StartTimeout is the time the client waits for the daemon to start.
+	// - default: 5s
+	StartTimeout time.Duration
+	// Executable is the binary started as the daemon.
+	// - default: the running executable
+	Executable string
+}
+
+// daemonConfig is the function that returns the configuration of the daemon with its defaults.
+func daemonConfig(cfg optional.Option[ConfigDaemon]) (r ConfigDaemon) {
+	r = ConfigDaemon{
+		Socket:       defaultDaemonSocket(filepath.Base(os.Args[0])),
+		IdleTimeout:  10 * time.Minute,
+		StartTimeout: 5 * time.Second,
+	}
+	if exe, err := os.Executable(); err == nil {
+		r.Executable = exe
+	}
+	if cfg.IsSome() {
+		config := cfg.Unwrap()
+		if config.Socket != "" {
+			r.Socket = config.Socket
+		}
+		if config.IdleTimeout > 0 {
+			r.IdleTimeout = config.IdleTimeout
+		}
+		if config.StartTimeout > 0 {
+			r.StartTimeout = config.StartTimeout
+		}
+		if config.Executable != "" {
+			r.Executable = config.Executable
+		}
+	}
+	return
+}
+
+// defaultDaemonSocket is the function that returns the default socket of the daemon of an app.
+// - the runtime dir of the user, private to it, or a dir for the user in the temp dir
+func defaultDaemonSocket(app string) (path string) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		path = filepath.Join(dir, app+".sock")
+		return
+	}
+	path = filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", app, os.Getuid()), "daemon.sock")
+	return
+}
+
+// daemonMessage is the struct that represents a message between the client and the daemon.
+// - the daemon greets with its version
+// - the client answers with the request, or asks a daemon from another version to shut down
+// - then stdio is streamed both ways, until the daemon sends the exit code
+type daemonMessage struct {
+	// Version is the version of the daemon.
+	Version string `json:"version,omitempty"`
+	// Shutdown asks the daemon to shut down.
+	Shutdown bool `json:"shutdown,omitempty"`
+
+	// Args are the args of the client, without the app name.
+	Args []string `json:"args,omitempty"`
+	// Env is the environment of the client.
+	// - it may hold credentials, it is only sent to a daemon of the same user
+	Env []string `json:"env,omitempty"`
+	// Dir is the working directory of the client.
+	Dir string `json:"dir,omitempty"`
+	// StdinTerminal is the flag that indicates if the stdin of the client is a terminal, e.g. for the prompts.
+	StdinTerminal bool `json:"stdin_terminal,omitempty"`
+	// StdoutTerminal is the flag that indicates if the stdout of the client is a terminal, e.g. for the colors.
+	StdoutTerminal bool `json:"stdout_terminal,omitempty"`
+	// StderrTerminal is the flag that indicates if the stderr of the client is a terminal, e.g. for the progress.
+	StderrTerminal bool `json:"stderr_terminal,omitempty"`
+
+	// Stream is the stream of the data: stdin, stdout or stderr.
+	Stream string `json:"stream,omitempty"`
+	// Data is a chunk of the stream.
+	Data []byte `json:"data,omitempty"`
+	// EOF is the end of the stream.
+	EOF bool `json:"eof,omitempty"`
+
+	// Done is the end of the command, with its exit code and error.
+	Done bool `json:"done,omitempty"`
+	// Code is the exit code of the command.
+	Code int `json:"code,omitempty"`
+	// Error is the error of the command, empty if it was already reported.
+	Error string `json:"error,omitempty"`
+}
+
+// RunDaemon is the method that runs the CLI through a daemon that keeps it in memory.
+// - the process acts as a client: it forwards args, env, working directory and stdio to the daemon and relays the exit code
+// - the daemon is started when it is not running, or when it is from another version
+// - the started daemon is the same binary, which serves when it finds itself started as the daemon
+// - the daemon leaves running the handlers that do not stop within the grace period, see ServeDaemon
+func (c CLI) RunDaemon(ctx context.Context, cfg optional.Option[ConfigDaemon]) (err error) {
+	config := daemonConfig(cfg)
+
+	// the process is the daemon
+	if socket := os.Getenv(envDaemonSocket); socket != "" {
+		os.Unsetenv(envDaemonSocket)
+		config.Socket = socket
+		err = c.ServeDaemon(ctx, optional.Some(config))
+		return
+	}
+
+	err = c.runDaemonClient(ctx, os.Args[1:], config)
+	return
+}
+
+// ServeDaemon is the method that runs the daemon, listening on the Unix socket.
+// - requests are run one at a time, since the env and working directory of the client are applied to the process
+// - only the user can connect: the socket is 0600 in a private dir, and the peer of each connection is checked where supported
+// - it returns when the daemon is idle for the idle timeout, when a client asks it to shut down or when the context is done
+// - a handler that does not return within the grace period after a timeout is left running while the next requests run,
+// seeing the env and working directory of their clients
+func (c CLI) ServeDaemon(ctx context.Context, cfg optional.Option[ConfigDaemon]) (err error) {
+	config := daemonConfig(cfg)
+
+	// socket
+	// - a socket left by a daemon that is gone is removed, any other file is left as is
+	err = prepareDaemonDir(filepath.Dir(config.Socket))
+	if err != nil {
+		return
+	}
+	if conn, derr := net.Dial("unix", config.Socket); derr == nil {
+		conn.Close()
+		err = ErrDaemonRunning
+		return
+	}
+	if info, serr := os.Lstat(config.Socket); serr == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			err = fmt.Errorf("%w: %s is not a socket", ErrDaemonUnsafe, config.Socket)
+			return
+		}
+		os.Remove(config.Socket)
+	}
+	ln, err := net.Listen("unix", config.Socket)
+	if err != nil {
+		return
+	}
+	err = os.Chmod(config.Socket, 0o600)
+	if err != nil {
+		ln.Close()
+		return
+	}
+
+	var (
+		once   sync.Once
+		stop   = func() { once.Do(func() { ln.Close() }) }
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		active int
+		run    sync.Mutex
+	)
+	defer wg.Wait()
+	defer stop()
+	idle := time.AfterFunc(config.IdleTimeout, stop)
+	defer idle.Stop()
+	ctxDone := context.AfterFunc(ctx, stop)
+	defer ctxDone()
+
+	version := c.daemonVersion()
+	for {
+		conn, aerr := ln.Accept()
+		if aerr != nil {
+			// closed by the idle timeout, a shutdown or the context
+			return
+		}
+
+		mu.Lock()
+		active++
+		idle.Stop()
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				active--
+				if active == 0 {
+					idle.Reset(config.IdleTimeout)
+				}
+				mu.Unlock()
+			}()
+			defer conn.Close()
+
+			if c.serveDaemonConn(ctx, conn, version, &run) {
+				stop()
+			}
+		}()
+	}
+}
+
+// serveDaemonConn is the method that serves a client of the daemon.
+// - it returns true if the client asked the daemon to shut down
+func (c CLI) serveDaemonConn(ctx context.Context, conn net.Conn, version string, run *sync.Mutex) (shutdown bool) {
+	// peer
+	// - a process of another user is never read from nor written to
+	if checkDaemonPeer(conn) != nil {
+		return
+	}
+
+	enc := &daemonEncoder{enc: json.NewEncoder(conn)}
+	dec := json.NewDecoder(conn)
+
+	// greeting
+	if enc.Encode(daemonMessage{Version: version}) != nil {
+		return
+	}
+	var req daemonMessage
+	if dec.Decode(&req) != nil {
+		return
+	}
+	if req.Shutdown {
+		shutdown = true
+		return
+	}
+
+	// stdin
+	// - the command is canceled if the client disconnects
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stdin, stdinW := io.Pipe()
+	defer stdin.Close()
+	go func() {
+		for {
+			var msg daemonMessage
+			if err := dec.Decode(&msg); err != nil {
+				stdinW.CloseWithError(ErrDaemonDisconnected)
+				cancel()
+				return
+			}
+			if len(msg.Data) > 0 {
+				if _, err := stdinW.Write(msg.Data); err != nil {
+					continue
+				}
+			}
+			if msg.EOF {
+				stdinW.Close()
+			}
+		}
+	}()
+
+	// run
+	// - the env and working directory of the client are applied for the duration of the command
+	// - the stdio are terminals if they are for the client
+	run.Lock()
+	restore, err := applyDaemonEnv(req.Env, req.Dir)
+	if err == nil {
+		cli := c
+		cli.Reader = &daemonStdin{PipeReader: stdin, isTerminal: req.StdinTerminal}
+		cli.Writer = &daemonStreamWriter{enc: enc, stream: "stdout", isTerminal: req.StdoutTerminal}
+		cli.ErrWriter = &daemonStreamWriter{enc: enc, stream: "stderr", isTerminal: req.StderrTerminal}
+		err = cli.run(ctx, req.Args)
+		restore()
+	}
+	run.Unlock()
+
+	// exit code
+	done := daemonMessage{Done: true, Code: ExitCode(err)}
+	var exitErr *ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.Err == nil) {
+		done.Error = err.Error()
+	}
+	enc.Encode(done)
+	return
+}
+
+// runDaemonClient is the method that runs the args through the daemon, starting it if needed.
+func (c CLI) runDaemonClient(ctx context.Context, args []string, config ConfigDaemon) (err error) {
+	version := c.daemonVersion()
+	err = prepareDaemonDir(filepath.Dir(config.Socket))
+	if err != nil {
+		return
+	}
+
+	// connect
+	// - a daemon from another version is asked to shut down, and a new one is started
+	// - a socket of another user is never talked to
+	conn, dec, daemonVersion, err := dialDaemon(config.Socket)
+	if errors.Is(err, ErrDaemonUnsafe) {
+		return
+	}
+	if err == nil && daemonVersion != version {
+		json.NewEncoder(conn).Encode(daemonMessage{Shutdown: true})
+		conn.Close()
+		err = ErrDaemonVersionMismatch
+		waitDaemonGone(config.Socket, config.StartTimeout)
+	}
+	if err != nil {
+		conn, dec, err = startDaemon(config, version)
+		if err != nil {
+			return
+		}
+	}
+	defer conn.Close()
+
+	// request
+	dir, _ := os.Getwd()
+	enc := &daemonEncoder{enc: json.NewEncoder(conn)}
+	err = enc.Encode(daemonMessage{
+		Args:           args,
+		Env:            os.Environ(),
+		Dir:            dir,
+		StdinTerminal:  isTerminal(c.reader()),
+		StdoutTerminal: isTerminal(c.writer()),
+		StderrTerminal: isTerminal(c.errWriter()),
+	})
+	if err != nil {
+		return
+	}
+
+	// stdin
+	go func() {
+		buf := make([]byte, 32*1024)
+		r := c.reader()
+		for {
+			n, rerr := r.Read(buf)
+			if n > 0 {
+				if enc.Encode(daemonMessage{Stream: "stdin", Data: append([]byte{}, buf[:n]...)}) != nil {
+					return
+				}
+			}
+			if rerr != nil {
+				enc.Encode(daemonMessage{Stream: "stdin", EOF: true})
+				return
+			}
+		}
+	}()
+	// - the connection is closed if the context is done, canceling the command
+	stopCtx := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stopCtx()
+
+	// stdout, stderr and exit code
+	for {
+		var msg daemonMessage
+		if dec.Decode(&msg) != nil {
+			err = ErrDaemonDisconnected
+			return
+		}
+		switch {
+		case msg.Done:
+			if msg.Code == 0 && msg.Error == "" {
+				return
+			}
+			exitErr := &ExitError{Code: msg.Code}
+			if msg.Error != "" {
+				exitErr.Err = errors.New(msg.Error)
+			}
+			err = exitErr
+			return
+		case msg.Stream == "stdout":
+			c.writer().Write(msg.Data)
+		case msg.Stream == "stderr":
+			c.errWriter().Write(msg.Data)
+		}
+	}
+}
+
+// daemonVersion is the method that returns the version the daemon reports.
+// - the modification time of the executable is included, so a rebuilt binary never talks to an old daemon
+func (c CLI) daemonVersion() (v string) {
+	v = c.Version
+	if exe, err := os.Executable(); err == nil {
+		if info, err := os.Stat(exe); err == nil {
+			v += fmt.Sprintf("+%d", info.ModTime().UnixNano())
+		}
+	}
+	return
+}
+
+// dialDaemon is the function that connects to the daemon and reads its version.
+// - the daemon must be a process of the user, where the peer can be checked
+func dialDaemon(socket string) (conn net.Conn, dec *json.Decoder, version string, err error) {
+	conn, err = net.Dial("unix", socket)
+	if err != nil {
+		return
+	}
+	err = checkDaemonPeer(conn)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	dec = json.NewDecoder(conn)
+	var greeting daemonMessage
+	err = dec.Decode(&greeting)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	version = greeting.Version
+	return
+}
+
+// startDaemon is the function that starts the daemon and connects to it.
+func startDaemon(config ConfigDaemon, version string) (conn net.Conn, dec *json.Decoder, err error) {
+	cmd := exec.Command(config.Executable)
+	cmd.Env = append(os.Environ(), envDaemonSocket+"="+config.Socket)
+	cmd.SysProcAttr = daemonSysProcAttr()
+	err = cmd.Start()
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrDaemonNotStarted, err)
+		return
+	}
+	go cmd.Wait()
+
+	// wait for the socket
+	deadline := time.Now().Add(config.StartTimeout)
+	for {
+		var v string
+		conn, dec, v, err = dialDaemon(config.Socket)
+		if errors.Is(err, ErrDaemonUnsafe) {
+			return
+		}
+		if err == nil {
+			if v != version {
+				conn.Close()
+				err = ErrDaemonVersionMismatch
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			err = ErrDaemonNotStarted
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// waitDaemonGone is the function that waits for a daemon that was asked to shut down to remove its socket.
+func waitDaemonGone(socket string, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(socket); err != nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// applyDaemonEnv is the function that applies the env and working directory of a client to the process.
+// - it returns the function that restores the previous ones
+// - nothing is applied if the working directory can not be changed, the command must not run elsewhere
+func applyDaemonEnv(env []string, dir string) (restore func(), err error) {
+	prevEnv := os.Environ()
+	prevDir, _ := os.Getwd()
+
+	if dir != "" {
+		err = os.Chdir(dir)
+		if err != nil {
+			return
+		}
+	}
+	setEnv(env)
+
+	restore = func() {
+		setEnv(prevEnv)
+		if prevDir != "" {
+			os.Chdir(prevDir)
+		}
+	}
+	return
+}
+
+// setEnv is the function that replaces the environment of the process.
+func setEnv(env []string) {
+	os.Clearenv()
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k != "" {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+// daemonEncoder is the struct that encodes messages to a connection from several goroutines.
+type daemonEncoder struct {
+	// mu is the mutex that guards the encoder.
+	mu sync.Mutex
+	// enc is the encoder of the connection.
+	enc *json.Encoder
+}
+
+// Encode is the method that encodes a message.
+func (e *daemonEncoder) Encode(msg daemonMessage) (err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err = e.enc.Encode(msg)
+	return
+}
+
+// daemonStreamWriter is the struct that writes a stream of the command to the client.
+type daemonStreamWriter struct {
+	// enc is the encoder of the connection.
+	enc *daemonEncoder
+	// stream is the name of the stream.
+	stream string
+	// isTerminal is the flag that indicates if the stream is a terminal for the client.
+	isTerminal bool
+}
+
+// terminal is the method that returns if the stream is a terminal for the client, see terminalStream.
+func (w *daemonStreamWriter) terminal() (ok bool) {
+	ok = w.isTerminal
+	return
+}
+
+// daemonStdin is the struct that reads the stdin forwarded by a daemon client.
+type daemonStdin struct {
+	*io.PipeReader
+	// isTerminal is the flag that indicates if the stdin is a terminal for the client.
+	isTerminal bool
+}
+
+// terminal is the method that returns if the stdin is a terminal for the client, see terminalStream.
+func (r *daemonStdin) terminal() (ok bool) {
+	ok = r.isTerminal
+	return
+}
+
+// Write is the method that writes a chunk of the stream.
+func (w *daemonStreamWriter) Write(p []byte) (n int, err error) {
+	err = w.enc.Encode(daemonMessage{Stream: w.stream, Data: p})
+	if err != nil {
+		return
+	}
+
+	n = len(p)
+	return
+}
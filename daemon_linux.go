@@ -0,0 +1,41 @@
+//go:build linux
+
+package gocli
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// checkDaemonPeer is the function that checks that the process on the other side of a connection is of the user.
+// - the credentials of the peer are read with SO_PEERCRED
+func checkDaemonPeer(conn net.Conn) (err error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		err = fmt.Errorf("%w: not a unix socket", ErrDaemonUnsafe)
+		return
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return
+	}
+
+	var cred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err == nil {
+		err = credErr
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrDaemonUnsafe, err)
+		return
+	}
+	if int(cred.Uid) != os.Getuid() {
+		err = fmt.Errorf("%w: peer of uid %d", ErrDaemonUnsafe, cred.Uid)
+	}
+	return
+}
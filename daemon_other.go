@@ -0,0 +1,20 @@
+//go:build !unix
+
+package gocli
+
+import (
+	"os"
+	"syscall"
+)
+
+// daemonSysProcAttr is the function that returns the attributes of the daemon process.
+func daemonSysProcAttr() (attr *syscall.SysProcAttr) {
+	return
+}
+
+// prepareDaemonDir is the function that makes sure the dir of the socket exists.
+// - it is created with 0700 if it does not exist, the owner can not be checked
+func prepareDaemonDir(dir string) (err error) {
+	err = os.MkdirAll(dir, 0o700)
+	return
+}
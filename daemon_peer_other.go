@@ -0,0 +1,11 @@
+//go:build !linux
+
+package gocli
+
+import "net"
+
+// checkDaemonPeer is the function that checks that the process on the other side of a connection is of the user.
+// - the credentials of the peer are not available, the socket is protected by its permissions and the ones of its dir
+func checkDaemonPeer(conn net.Conn) (err error) {
+	return
+}
@@ -0,0 +1,193 @@
+package gocli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_RunDaemon is the test for the method RunDaemon.
+func TestCLI_RunDaemon(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func(version string) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name: "greet",
+			Handler: func(i gocli.Input) (err error) {
+				in, _ := io.ReadAll(i.Reader)
+				fmt.Fprintf(i.Writer, "hello %v, %s, %s", i.Flags["name"], in, os.Getenv("GREETING"))
+				fmt.Fprint(i.ErrWriter, "greeted")
+				return
+			},
+		})
+		cm.AddCommand(gocli.Command{
+			Name: "fail",
+			Handler: func(i gocli.Input) (err error) {
+				err = &gocli.ExitError{Code: 3, Err: errors.New("greet failed")}
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Version = version
+		return
+	}
+
+	// startDaemon is the function that starts a daemon and waits for its socket
+	startDaemon := func(t *testing.T, cli gocli.CLI, cfg gocli.ConfigDaemon) (done chan error) {
+		done = make(chan error, 1)
+		go func() { done <- cli.ServeDaemon(context.Background(), optional.Some(cfg)) }()
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(cfg.Socket)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		return
+	}
+
+	// socket is the function that returns the path of a socket, short enough for unix sockets
+	socket := func(t *testing.T) (path string) {
+		dir, err := os.MkdirTemp("", "gocli")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		path = filepath.Join(dir, "d.sock")
+		return
+	}
+
+	// setArgs is the function that sets the args of the process for a test
+	setArgs := func(t *testing.T, args ...string) {
+		prev := os.Args
+		os.Args = append([]string{"app"}, args...)
+		t.Cleanup(func() { os.Args = prev })
+	}
+
+	t.Run("success - case 01: args, env and stdio are forwarded to the daemon", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t), IdleTimeout: 50 * time.Millisecond}
+		setArgs(t, "greet", "--name", "bob")
+		t.Setenv("GREETING", "hi")
+		done := startDaemon(t, newCLI("1.0.0"), cfg)
+
+		var stdout, stderr bytes.Buffer
+		client := newCLI("1.0.0")
+		client.Reader = strings.NewReader("from stdin")
+		client.Writer = &stdout
+		client.ErrWriter = &stderr
+
+		// act
+		err := client.RunDaemon(context.Background(), optional.Some(cfg))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "hello bob, from stdin, hi", stdout.String())
+		require.Equal(t, "greeted", stderr.String())
+		require.NoError(t, <-done)
+	})
+
+	t.Run("success - case 02: exit code is relayed", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t), IdleTimeout: 50 * time.Millisecond}
+		setArgs(t, "fail")
+		done := startDaemon(t, newCLI("1.0.0"), cfg)
+		client := newCLI("1.0.0")
+		client.Reader = strings.NewReader("")
+
+		// act
+		err := client.RunDaemon(context.Background(), optional.Some(cfg))
+
+		// assert
+		require.EqualError(t, err, "greet failed")
+		require.Equal(t, 3, gocli.ExitCode(err))
+		require.NoError(t, <-done)
+	})
+
+	t.Run("success - case 03: daemon shuts down after the idle timeout", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t), IdleTimeout: 20 * time.Millisecond}
+
+		// act
+		done := startDaemon(t, newCLI("1.0.0"), cfg)
+
+		// assert
+		require.NoError(t, <-done)
+		_, err := os.Stat(cfg.Socket)
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("success - case 04: socket is private to the user", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t), IdleTimeout: 50 * time.Millisecond}
+
+		// act
+		done := startDaemon(t, newCLI("1.0.0"), cfg)
+		info, err := os.Stat(cfg.Socket)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		require.NoError(t, <-done)
+	})
+
+	t.Run("failure - case 01: daemon from another version is shut down", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t), StartTimeout: 100 * time.Millisecond, Executable: "true"}
+		setArgs(t, "greet")
+		done := startDaemon(t, newCLI("1.0.0"), cfg)
+
+		// act
+		err := newCLI("2.0.0").RunDaemon(context.Background(), optional.Some(cfg))
+
+		// assert
+		require.NoError(t, <-done)
+		require.ErrorIs(t, err, gocli.ErrDaemonNotStarted)
+	})
+
+	t.Run("failure - case 02: daemon already running", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t), IdleTimeout: 50 * time.Millisecond}
+		done := startDaemon(t, newCLI("1.0.0"), cfg)
+
+		// act
+		err := newCLI("1.0.0").ServeDaemon(context.Background(), optional.Some(cfg))
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrDaemonRunning)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("failure - case 03: dir of the socket is not private", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t)}
+		require.NoError(t, os.Chmod(filepath.Dir(cfg.Socket), 0o755))
+
+		// act
+		errServe := newCLI("1.0.0").ServeDaemon(context.Background(), optional.Some(cfg))
+		errClient := newCLI("1.0.0").RunDaemon(context.Background(), optional.Some(cfg))
+
+		// assert
+		require.ErrorIs(t, errServe, gocli.ErrDaemonUnsafe)
+		require.ErrorIs(t, errClient, gocli.ErrDaemonUnsafe)
+	})
+
+	t.Run("failure - case 04: file at the socket path is not removed", func(t *testing.T) {
+		// arrange
+		cfg := gocli.ConfigDaemon{Socket: socket(t)}
+		require.NoError(t, os.WriteFile(cfg.Socket, []byte("data"), 0o600))
+
+		// act
+		err := newCLI("1.0.0").ServeDaemon(context.Background(), optional.Some(cfg))
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrDaemonUnsafe)
+		require.FileExists(t, cfg.Socket)
+	})
+}
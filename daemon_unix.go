@@ -0,0 +1,36 @@
+//go:build unix
+
+package gocli
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// daemonSysProcAttr is the function that returns the attributes of the daemon process.
+// - the daemon runs in its own session, so it outlives the client and its terminal
+func daemonSysProcAttr() (attr *syscall.SysProcAttr) {
+	attr = &syscall.SysProcAttr{Setsid: true}
+	return
+}
+
+// prepareDaemonDir is the function that makes sure the dir of the socket is private to the user.
+// - it is created with 0700 if it does not exist
+// - an existing dir must be owned by the user, with no permissions for the others
+func prepareDaemonDir(dir string) (err error) {
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !info.IsDir() || !ok || int(st.Uid) != os.Getuid() || info.Mode().Perm()&0o077 != 0 {
+		err = fmt.Errorf("%w: %s must be a dir owned by the user and private to it", ErrDaemonUnsafe, dir)
+	}
+	return
+}
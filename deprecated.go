@@ -0,0 +1,32 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDeprecated is the error that returns when a deprecated item is used in strict mode.
+	ErrDeprecated = errors.New("deprecated")
+)
+
+// Deprecated is the struct that represents the deprecation of a command, group or flag.
+type Deprecated struct {
+	// Message is the reason of the deprecation.
+	Message string `json:"message,omitempty"`
+	// Replacement is what should be used instead, e.g. "db migrate up" or "--region".
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// warning is the method that returns the warning shown when the deprecated item is used.
+// - kind is the kind of item: command, group or flag
+func (d Deprecated) warning(kind, name string) (w string) {
+	w = fmt.Sprintf("%s %q is deprecated", kind, name)
+	if d.Message != "" {
+		w += ": " + d.Message
+	}
+	if d.Replacement != "" {
+		w += fmt.Sprintf(", use %q instead", d.Replacement)
+	}
+	return
+}
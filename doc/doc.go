@@ -0,0 +1,158 @@
+// Package doc generates the documentation of a command manager tree:
+// one roff man page (section 1) and one Markdown page per group and command,
+// cross-linked between parents and children.
+//
+// The output is deterministic, so the generated docs can be diffed in review.
+package doc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LNMMusic/gocli"
+)
+
+// ErrPageCollision is the error that returns when two pages have the same file name,
+// e.g. the man pages of the command `db-migrate` and of the command `migrate` of the group `db`.
+var ErrPageCollision = errors.New("doc page name collision")
+
+// node is the struct that represents a page of the documentation, either a group or a command.
+type node struct {
+	// path is the full path of the page, starting with the name of the root.
+	path []string
+	// group is the group of the page, nil for a command.
+	group *gocli.CommanderManager
+	// cmd is the command of the page, nil for a group.
+	cmd *gocli.Command
+	// parent is the parent of the page, nil for the root.
+	parent *node
+	// children are the visible commands and groups of a group page, commands first.
+	children []*node
+}
+
+// description is the method that returns the description of the page.
+func (n *node) description() (d string) {
+	if n.cmd != nil {
+		d = n.cmd.Description
+		return
+	}
+	d = n.group.Description
+	return
+}
+
+// deprecated is the method that returns the deprecation of the page.
+func (n *node) deprecated() (d *gocli.Deprecated) {
+	if n.cmd != nil {
+		d = n.cmd.Deprecated
+		return
+	}
+	d = n.group.Deprecated
+	return
+}
+
+// tree is the function that builds the pages of a command manager tree.
+// - hidden groups, commands and flags are left out
+func tree(cm *gocli.CommanderManager) (root *node) {
+	root = &node{path: []string{cm.Name}, group: cm}
+	build(root)
+	return
+}
+
+// build is the function that builds the children of a group page.
+func build(n *node) {
+	for i := range n.group.Cmds {
+		cmd := &n.group.Cmds[i]
+		if cmd.Hidden {
+			continue
+		}
+		n.children = append(n.children, &node{path: appendPath(n.path, cmd.Name), cmd: cmd, parent: n})
+	}
+	for _, sub := range n.group.CommandManagers {
+		if sub.Hidden {
+			continue
+		}
+		child := &node{path: appendPath(n.path, sub.Name), group: sub, parent: n}
+		build(child)
+		n.children = append(n.children, child)
+	}
+}
+
+// walk is the function that calls fn for a page and all its descendants, depth first.
+func walk(n *node, fn func(n *node) (err error)) (err error) {
+	err = fn(n)
+	if err != nil {
+		return
+	}
+	for _, child := range n.children {
+		err = walk(child, fn)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// checkNames is the function that checks that no two pages of the tree have the same file name.
+// - it is called before any page is written, so a colliding tree writes nothing
+func checkNames(root *node, name func(n *node) (name string)) (err error) {
+	paths := make(map[string][]string)
+	err = walk(root, func(n *node) (err error) {
+		if other, ok := paths[name(n)]; ok {
+			err = fmt.Errorf("%w: %s (%q and %q)", ErrPageCollision, name(n), strings.Join(other, " "), strings.Join(n.path, " "))
+			return
+		}
+		paths[name(n)] = n.path
+		return
+	})
+	return
+}
+
+// appendPath is the function that returns a copy of the path with a new name.
+func appendPath(path []string, name string) (r []string) {
+	r = append(append([]string{}, path...), name)
+	return
+}
+
+// visibleFlags is the function that returns the flags of a page that are not hidden.
+// - the persistent flags of the groups of the page are included, nearest first
+func visibleFlags(n *node) (flags gocli.Flags) {
+	var all gocli.Flags
+	if n.cmd != nil {
+		all = append(all, n.cmd.Flags...)
+	}
+	for g := n; g != nil; g = g.parent {
+		if g.group == nil {
+			continue
+		}
+		for _, f := range g.group.Flags {
+			if _, ok := all.Find(f.Name); !ok {
+				all = append(all, f)
+			}
+		}
+	}
+	for _, f := range all {
+		if !f.Hidden {
+			flags = append(flags, f)
+		}
+	}
+	return
+}
+
+// writeFile is the function that writes a page in the dir.
+func writeFile(dir, name, content string) (err error) {
+	err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+	return
+}
+
+// usage is the function that returns the usage line of a page.
+func usage(n *node) (u string) {
+	u = strings.Join(n.path, " ")
+	if n.group != nil {
+		u += " <command>"
+	}
+	u += " [flags] [options]"
+	return
+}
@@ -0,0 +1,179 @@
+package doc_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/gocli/doc"
+	"github.com/stretchr/testify/require"
+)
+
+// newCommanderManager is the function that returns the command manager used in the tests.
+func newCommanderManager() (cm *gocli.CommanderManager) {
+	cm = gocli.NewCommanderManager("app", "app description")
+	cm.AddCommand(gocli.Command{Name: "debug", Hidden: true})
+	db := cm.Group("db", "database commands")
+	db.AddCommand(gocli.Command{
+		Name:        "migrate",
+		Description: "runs the migrations",
+		Aliases:     []string{"m"},
+		Examples:    []string{"app db migrate --env prod"},
+		Flags: gocli.Flags{
+			{Name: "env", Description: "environment", Default: "dev"},
+			{Name: "dry", Description: "dry run", Deprecated: &gocli.Deprecated{Replacement: "--plan"}},
+			{Name: "legacy", Hidden: true},
+		},
+	})
+	return
+}
+
+// readDir is the function that returns the files of a dir with their content.
+func readDir(t *testing.T, dir string) (files map[string]string) {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	files = make(map[string]string)
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		files[entry.Name()] = string(b)
+	}
+	return
+}
+
+// keys is the function that returns the sorted keys of a map.
+func keys(m map[string]string) (k []string) {
+	for key := range m {
+		k = append(k, key)
+	}
+	sort.Strings(k)
+	return
+}
+
+// TestGenManTree is the test for the function GenManTree.
+func TestGenManTree(t *testing.T) {
+	t.Run("success - case 01: one page per group and command", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+
+		// act
+		err := doc.GenManTree(newCommanderManager(), doc.ManHeader{Source: "app 1.0.0", Manual: "App Manual"}, dir)
+
+		// assert
+		require.NoError(t, err)
+		files := readDir(t, dir)
+		require.Equal(t, []string{"app-db-migrate.1", "app-db.1", "app.1"}, keys(files))
+		require.Equal(t, `.TH "APP\-DB\-MIGRATE" "1" "" "app 1.0.0" "App Manual"
+.SH NAME
+app\-db\-migrate \- runs the migrations
+.SH SYNOPSIS
+.B app db migrate [flags] [options]
+.SH DESCRIPTION
+runs the migrations
+.SH ALIASES
+m
+.SH OPTIONS
+.TP
+\fB\-\-env\fR
+environment (default: dev)
+.TP
+\fB\-\-dry\fR
+dry run Deprecated. Use \-\-plan instead.
+.SH EXAMPLES
+.nf
+app db migrate \-\-env prod
+.fi
+.SH SEE ALSO
+.BR app\-db (1)
+`, files["app-db-migrate.1"])
+		require.Contains(t, files["app-db.1"], ".SH SEE ALSO\n.BR app (1),\n.BR app\\-db\\-migrate (1)\n")
+	})
+
+	t.Run("success - case 02: header is escaped", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+
+		// act
+		err := doc.GenManTree(newCommanderManager(), doc.ManHeader{Source: `app "next"`, Manual: `App\Manual`}, dir)
+
+		// assert
+		require.NoError(t, err)
+		files := readDir(t, dir)
+		require.Contains(t, files["app.1"], `.TH "APP" "1" "" "app \(dqnext\(dq" "App\eManual"`+"\n")
+	})
+
+	t.Run("failure - case 01: two pages with the same name", func(t *testing.T) {
+		// arrange
+		dir := filepath.Join(t.TempDir(), "man")
+		cm := newCommanderManager()
+		cm.AddCommand(gocli.Command{Name: "db-migrate"})
+
+		// act
+		err := doc.GenManTree(cm, doc.ManHeader{}, dir)
+
+		// assert
+		require.ErrorIs(t, err, doc.ErrPageCollision)
+		require.EqualError(t, err, `doc page name collision: app-db-migrate ("app db-migrate" and "app db migrate")`)
+		require.NoDirExists(t, dir)
+	})
+}
+
+// TestGenMarkdownTree is the test for the function GenMarkdownTree.
+func TestGenMarkdownTree(t *testing.T) {
+	t.Run("success - case 01: one page per group and command", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+
+		// act
+		err := doc.GenMarkdownTree(newCommanderManager(), dir)
+
+		// assert
+		require.NoError(t, err)
+		files := readDir(t, dir)
+		require.Equal(t, []string{"app.md", "app_db.md", "app_db_migrate.md"}, keys(files))
+		require.Equal(t, "# app db migrate\n"+
+			"\nruns the migrations\n"+
+			"\n## Usage\n\n```\napp db migrate [flags] [options]\n```\n"+
+			"\n## Aliases\n\n`m`\n"+
+			"\n## Flags\n\n| Flag | Description | Default |\n| --- | --- | --- |\n"+
+			"| `--env` | environment | `dev` |\n"+
+			"| `--dry` | dry run **Deprecated.** Use --plan instead. |  |\n"+
+			"\n## Examples\n\n```\napp db migrate --env prod\n```\n"+
+			"\n## See Also\n\n- [app db](app_db.md) - database commands\n", files["app_db_migrate.md"])
+		require.Equal(t, "# app\n"+
+			"\napp description\n"+
+			"\n## Usage\n\n```\napp <command> [flags] [options]\n```\n"+
+			"\n## Commands\n\n- [app db](app_db.md) - database commands\n", files["app.md"])
+	})
+
+	t.Run("success - case 02: output is deterministic", func(t *testing.T) {
+		// arrange
+		dir1, dir2 := t.TempDir(), t.TempDir()
+
+		// act
+		err1 := doc.GenMarkdownTree(newCommanderManager(), dir1)
+		err2 := doc.GenMarkdownTree(newCommanderManager(), dir2)
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.Equal(t, readDir(t, dir1), readDir(t, dir2))
+	})
+	t.Run("failure - case 01: two pages with the same name", func(t *testing.T) {
+		// arrange
+		dir := filepath.Join(t.TempDir(), "md")
+		cm := newCommanderManager()
+		cm.AddCommand(gocli.Command{Name: "db_migrate"})
+
+		// act
+		err := doc.GenMarkdownTree(cm, dir)
+
+		// assert
+		require.ErrorIs(t, err, doc.ErrPageCollision)
+		require.EqualError(t, err, `doc page name collision: app_db_migrate.md ("app db_migrate" and "app db migrate")`)
+		require.NoDirExists(t, dir)
+	})
+}
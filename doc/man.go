@@ -0,0 +1,184 @@
+package doc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LNMMusic/gocli"
+)
+
+// ManHeader is the struct that wraps the header of the man pages.
+type ManHeader struct {
+	// Section is the section of the man pages.
+	// - default: 1
+	Section string
+	// Date is the date of the man pages, e.g. "2023-10-01".
+	// - it is left empty by default, so the output does not change between runs
+	Date string
+	// Source is the source of the man pages, e.g. "app 1.2.0".
+	Source string
+	// Manual is the title of the manual, e.g. "App Manual".
+	Manual string
+}
+
+// GenManTree is the function that writes one man page per group and command of the tree in dir.
+// - the pages are named after their path, e.g. app-db-migrate.1
+// - ErrPageCollision is returned if two pages have the same name, e.g. `db-migrate` and `db migrate`
+func GenManTree(cm *gocli.CommanderManager, header ManHeader, dir string) (err error) {
+	if header.Section == "" {
+		header.Section = "1"
+	}
+
+	root := tree(cm)
+	err = checkNames(root, manName)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return
+	}
+
+	err = walk(root, func(n *node) (err error) {
+		err = writeFile(dir, manName(n)+"."+header.Section, manPage(n, header))
+		return
+	})
+	return
+}
+
+// manName is the function that returns the name of the man page of a node.
+func manName(n *node) (name string) {
+	name = strings.Join(n.path, "-")
+	return
+}
+
+// manPage is the function that returns the roff man page of a node.
+func manPage(n *node, header ManHeader) (page string) {
+	var b strings.Builder
+
+	// header
+	fmt.Fprintf(&b, ".TH \"%s\" \"%s\" \"%s\" \"%s\" \"%s\"\n",
+		roffArg(strings.ToUpper(manName(n))), roffArg(header.Section), roffArg(header.Date), roffArg(header.Source), roffArg(header.Manual))
+
+	// name
+	fmt.Fprintf(&b, ".SH NAME\n%s", roff(manName(n)))
+	if d := n.description(); d != "" {
+		fmt.Fprintf(&b, " \\- %s", roff(d))
+	}
+	b.WriteString("\n")
+
+	// synopsis
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n.B %s\n", roff(usage(n)))
+
+	// description
+	if d := n.description(); d != "" {
+		fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", roff(d))
+	}
+	if d := n.deprecated(); d != nil {
+		fmt.Fprintf(&b, ".PP\n\\fBDeprecated.\\fR %s\n", roff(deprecation(*d)))
+	}
+
+	// aliases
+	if n.cmd != nil && len(n.cmd.Aliases) > 0 {
+		fmt.Fprintf(&b, ".SH ALIASES\n%s\n", roff(strings.Join(n.cmd.Aliases, ", ")))
+	}
+
+	// flags
+	if flags := visibleFlags(n); len(flags) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, f := range flags {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", roff("--"+f.Name), roff(flagDescription(f)))
+		}
+	}
+
+	// flag groups
+	if n.cmd != nil && len(n.cmd.FlagGroups) > 0 {
+		b.WriteString(".SH FLAG GROUPS\n")
+		for _, g := range n.cmd.FlagGroups {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", roff(g.Names()), roff(g.Description()))
+		}
+	}
+
+	// examples
+	if n.cmd != nil && len(n.cmd.Examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n.nf\n")
+		for _, example := range n.cmd.Examples {
+			fmt.Fprintf(&b, "%s\n", roff(example))
+		}
+		b.WriteString(".fi\n")
+	}
+
+	// commands
+	if len(n.children) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, child := range n.children {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", roff(child.path[len(child.path)-1]), roff(child.description()))
+		}
+	}
+
+	// see also: parent and children
+	var refs []string
+	if n.parent != nil {
+		refs = append(refs, manName(n.parent))
+	}
+	for _, child := range n.children {
+		refs = append(refs, manName(child))
+	}
+	if len(refs) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		for i, ref := range refs {
+			sep := ","
+			if i == len(refs)-1 {
+				sep = ""
+			}
+			fmt.Fprintf(&b, ".BR %s (%s)%s\n", roff(ref), header.Section, sep)
+		}
+	}
+
+	page = b.String()
+	return
+}
+
+// roff is the function that escapes a text for roff.
+func roff(text string) (r string) {
+	r = strings.ReplaceAll(text, `\`, `\e`)
+	r = strings.ReplaceAll(r, "-", `\-`)
+
+	// lines starting with a control character
+	lines := strings.Split(r, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	r = strings.Join(lines, "\n")
+	return
+}
+
+// roffArg is the function that escapes a text for a quoted argument of a roff request, e.g. .TH
+// - quotes and line breaks would end the argument, so they are replaced
+func roffArg(text string) (r string) {
+	r = strings.ReplaceAll(roff(text), `"`, `\(dq`)
+	r = strings.ReplaceAll(r, "\n", " ")
+	return
+}
+
+// flagDescription is the function that returns the description of a flag with its default and deprecation.
+func flagDescription(f gocli.Flag) (d string) {
+	d = f.Describe(nil, true)
+	if f.Deprecated != nil {
+		d = strings.TrimSpace(d + " Deprecated. " + deprecation(*f.Deprecated))
+	}
+	return
+}
+
+// deprecation is the function that returns the message and replacement of a deprecation.
+func deprecation(d gocli.Deprecated) (msg string) {
+	msg = d.Message
+	if d.Replacement != "" {
+		msg = strings.TrimSpace(msg + " Use " + d.Replacement + " instead.")
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package doc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LNMMusic/gocli"
+)
+
+// GenMarkdownTree is the function that writes one Markdown page per group and command of the tree in dir.
+// - the pages are named after their path, e.g. app_db_migrate.md
+// - ErrPageCollision is returned if two pages have the same name, e.g. `db_migrate` and `db migrate`
+func GenMarkdownTree(cm *gocli.CommanderManager, dir string) (err error) {
+	root := tree(cm)
+	err = checkNames(root, markdownName)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return
+	}
+
+	err = walk(root, func(n *node) (err error) {
+		err = writeFile(dir, markdownName(n), markdownPage(n))
+		return
+	})
+	return
+}
+
+// markdownName is the function that returns the file name of the Markdown page of a node.
+func markdownName(n *node) (name string) {
+	name = strings.Join(n.path, "_") + ".md"
+	return
+}
+
+// markdownPage is the function that returns the Markdown page of a node.
+func markdownPage(n *node) (page string) {
+	var b strings.Builder
+
+	// title and description
+	fmt.Fprintf(&b, "# %s\n", strings.Join(n.path, " "))
+	if d := n.description(); d != "" {
+		fmt.Fprintf(&b, "\n%s\n", d)
+	}
+	if d := n.deprecated(); d != nil {
+		fmt.Fprintf(&b, "\n**Deprecated.** %s\n", deprecation(*d))
+	}
+
+	// usage
+	fmt.Fprintf(&b, "\n## Usage\n\n```\n%s\n```\n", usage(n))
+
+	// aliases
+	if n.cmd != nil && len(n.cmd.Aliases) > 0 {
+		fmt.Fprintf(&b, "\n## Aliases\n\n")
+		for i, alias := range n.cmd.Aliases {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "`%s`", alias)
+		}
+		b.WriteString("\n")
+	}
+
+	// flags
+	if flags := visibleFlags(n); len(flags) > 0 {
+		b.WriteString("\n## Flags\n\n| Flag | Description | Default |\n| --- | --- | --- |\n")
+		for _, f := range flags {
+			def := ""
+			if f.Default != "" {
+				def = "`" + f.DefaultText() + "`"
+			}
+			desc := f.Describe(nil, false)
+			if f.Deprecated != nil {
+				desc = strings.TrimSpace(desc + " **Deprecated.** " + deprecation(*f.Deprecated))
+			}
+			fmt.Fprintf(&b, "| `--%s` | %s | %s |\n", f.Name, markdownCell(desc), def)
+		}
+	}
+
+	// flag groups
+	if n.cmd != nil && len(n.cmd.FlagGroups) > 0 {
+		b.WriteString("\n## Flag Groups\n\n")
+		for _, g := range n.cmd.FlagGroups {
+			fmt.Fprintf(&b, "- `%s`: %s\n", g.Names(), g.Description())
+		}
+	}
+
+	// examples
+	if n.cmd != nil && len(n.cmd.Examples) > 0 {
+		fmt.Fprintf(&b, "\n## Examples\n\n```\n%s\n```\n", strings.Join(n.cmd.Examples, "\n"))
+	}
+
+	// commands
+	if len(n.children) > 0 {
+		b.WriteString("\n## Commands\n\n")
+		for _, child := range n.children {
+			fmt.Fprintf(&b, "- [%s](%s)", strings.Join(child.path, " "), markdownName(child))
+			if d := child.description(); d != "" {
+				fmt.Fprintf(&b, " - %s", d)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	// see also: parent
+	if n.parent != nil {
+		fmt.Fprintf(&b, "\n## See Also\n\n- [%s](%s)", strings.Join(n.parent.path, " "), markdownName(n.parent))
+		if d := n.parent.description(); d != "" {
+			fmt.Fprintf(&b, " - %s", d)
+		}
+		b.WriteString("\n")
+	}
+
+	page = b.String()
+	return
+}
+
+// markdownCell is the function that escapes a text for a Markdown table cell.
+func markdownCell(text string) (r string) {
+	r = strings.ReplaceAll(text, "|", `\|`)
+	r = strings.ReplaceAll(r, "\n", " ")
+	return
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/LNMMusic/gocli"
 	"github.com/LNMMusic/optional"
@@ -31,7 +32,7 @@ func main() {
 	})
 	// - run
 	if err := cli.Run(); err != nil {
-		fmt.Println(err)
-		return
+		cli.RenderError(err)
+		os.Exit(gocli.ExitCode(err))
 	}
 }
\ No newline at end of file
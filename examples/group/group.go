@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/LNMMusic/gocli"
 	"github.com/LNMMusic/optional"
@@ -40,9 +41,11 @@ func main() {
 			return nil
 		},
 	})
+	// - run ping when the group itself is run
+	group.(gocli.GroupConfigurer).SetDefault("ping")
 	// - run
 	if err := cli.Run(); err != nil {
-		fmt.Println(err)
-		return
+		cli.RenderError(err)
+		os.Exit(gocli.ExitCode(err))
 	}
 }
\ No newline at end of file
@@ -0,0 +1,105 @@
+// Command otel is an example of an adapter of the gocli Tracer to OpenTelemetry.
+// - the spans of the runs are exported to stdout by the OpenTelemetry SDK
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// otelTracer is the struct that adapts an OpenTelemetry tracer to a gocli Tracer.
+type otelTracer struct {
+	// t is the OpenTelemetry tracer.
+	t trace.Tracer
+}
+
+// Start is the method that starts an OpenTelemetry span, child of the span of the context if any.
+func (o otelTracer) Start(ctx context.Context, name string) (context.Context, gocli.Span) {
+	ctx, span := o.t.Start(ctx, name)
+	return ctx, otelSpan{span}
+}
+
+// otelSpan is the struct that adapts an OpenTelemetry span to a gocli Span.
+type otelSpan struct {
+	trace.Span
+}
+
+// SetAttributes is the method that sets the attributes of the span, with the OpenTelemetry type of their values.
+func (s otelSpan) SetAttributes(attrs ...gocli.Attribute) {
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			s.Span.SetAttributes(attribute.String(a.Key, v))
+		case bool:
+			s.Span.SetAttributes(attribute.Bool(a.Key, v))
+		case int:
+			s.Span.SetAttributes(attribute.Int(a.Key, v))
+		case []string:
+			s.Span.SetAttributes(attribute.StringSlice(a.Key, v))
+		default:
+			s.Span.SetAttributes(attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+}
+
+// RecordError is the method that records the error of the span and marks it as failed.
+func (s otelSpan) RecordError(err error) {
+	s.Span.RecordError(err)
+	s.Span.SetStatus(codes.Error, err.Error())
+}
+
+// End is the method that ends the span.
+func (s otelSpan) End() {
+	s.Span.End()
+}
+
+func main() {
+	// tracing
+	// - the provider exports the spans to stdout when it is shut down
+	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	defer provider.Shutdown(context.Background())
+
+	// cli
+	// - config
+	cli := gocli.NewCLI(
+		gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()),
+		gocli.NewCommanderManager("otel", "otel example"),
+	)
+	cli.Tracer = otelTracer{t: provider.Tracer("github.com/LNMMusic/gocli")}
+	// - add commands
+	cli.AddCommand(gocli.Command{
+		Name: "hello",
+		Description: "this command prints hello world, in a span of its own",
+		Flags: gocli.Flags{
+			{Name: "name", Description: "name to greet", Default: "world"},
+		},
+		Handler: func(i gocli.Input) error {
+			// the context carries the span of the handler
+			_, span := provider.Tracer("otel-example").Start(i.Context, "greet")
+			defer span.End()
+
+			fmt.Printf("hello %v\n", i.Flags["name"])
+			return nil
+		},
+	})
+	// - run
+	if err := cli.Run(); err != nil {
+		cli.RenderError(err)
+		provider.Shutdown(context.Background())
+		os.Exit(gocli.ExitCode(err))
+	}
+}
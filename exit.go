@@ -0,0 +1,60 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExitError is the struct that represents an error with the exit code the process should end with.
+type ExitError struct {
+	// Code is the exit code.
+	Code int
+	// Err is the error, nil if it was already reported (e.g. by an external plugin).
+	Err error
+}
+
+// Error is the method that returns the message of the error.
+func (e *ExitError) Error() (msg string) {
+	if e.Err == nil {
+		msg = fmt.Sprintf("exit status %d", e.Code)
+		return
+	}
+
+	msg = e.Err.Error()
+	return
+}
+
+// Unwrap is the method that returns the wrapped error.
+func (e *ExitError) Unwrap() (err error) {
+	err = e.Err
+	return
+}
+
+// ExitCode is the function that returns the exit code the process should end with for an error.
+// - 0 for no error, the code of an ExitError, ExitCodePanic for a PanicError, ExitCodeTimeout for a TimeoutError, or 1 for any other error
+func ExitCode(err error) (code int) {
+	if err == nil {
+		return
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		code = ExitCodePanic
+		return
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		code = ExitCodeTimeout
+		return
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.Code
+		return
+	}
+
+	code = 1
+	return
+}
@@ -0,0 +1,213 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrResponseFile is the error that returns when a response file can not be read or split into args.
+	ErrResponseFile = errors.New("invalid response file")
+	// ErrResponseFileDepth is the error that returns when response files are nested deeper than MaxResponseFileDepth.
+	ErrResponseFileDepth = errors.New("response files nested too deep")
+	// ErrEnvUndefined is the error that returns when an interpolated environment variable is not defined.
+	ErrEnvUndefined = errors.New("environment variable is not defined")
+	// ErrExpandedArgSpace is the error that returns when an expanded arg of a command without raw args has white space.
+	ErrExpandedArgSpace = errors.New("expanded arg has white space")
+)
+
+// MaxResponseFileDepth is the maximum nesting of response files, e.g. @a.txt containing @b.txt is a depth of 2.
+const MaxResponseFileDepth = 10
+
+// expandResponseFiles is the function that replaces the @path args by the args in the file at path.
+// - the args in a file may also be @path args, expanded up to MaxResponseFileDepth
+// - @@text is not expanded, it is the literal arg @text
+func expandResponseFiles(args []string) (r []string, err error) {
+	r, err = expandResponseFilesDepth(args, 0)
+	if err != nil {
+		r = nil
+	}
+	return
+}
+
+// expandResponseFilesDepth is the function that expands the response files of args found at a nesting depth.
+func expandResponseFilesDepth(args []string, depth int) (r []string, err error) {
+	r = make([]string, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "@@"):
+			r = append(r, arg[1:])
+			continue
+		case !strings.HasPrefix(arg, "@") || arg == "@":
+			r = append(r, arg)
+			continue
+		}
+		if depth >= MaxResponseFileDepth {
+			err = fmt.Errorf("%w: %s (max %d)", ErrResponseFileDepth, arg, MaxResponseFileDepth)
+			return
+		}
+
+		// read
+		path := arg[1:]
+		content, rerr := os.ReadFile(path)
+		if rerr != nil {
+			err = fmt.Errorf("%w: %s", ErrResponseFile, rerr)
+			return
+		}
+		fileArgs, serr := splitResponseFile(string(content))
+		if serr != nil {
+			err = fmt.Errorf("%w: %s: %s", ErrResponseFile, path, serr)
+			return
+		}
+
+		// nested
+		fileArgs, err = expandResponseFilesDepth(fileArgs, depth+1)
+		if err != nil {
+			return
+		}
+		r = append(r, fileArgs...)
+	}
+	return
+}
+
+// checkExpandedArgs is the function that checks that no expanded arg has white space.
+// - the parser gets the args joined with spaces, so such an arg would be split again, e.g. "a b" into a and b
+// - commands with raw args get the args as they are, so they are not checked
+func checkExpandedArgs(args []string) (err error) {
+	for _, arg := range args {
+		if strings.ContainsFunc(arg, unicode.IsSpace) {
+			err = fmt.Errorf("%w: %q, only commands with raw args accept it", ErrExpandedArgSpace, arg)
+			return
+		}
+	}
+	return
+}
+
+// splitResponseFile is the function that splits the content of a response file into args.
+// - args are separated by white space, including new lines
+// - single quotes keep the text as is, double quotes allow \" and \\ escapes
+// - outside quotes a backslash escapes the next char, e.g. a space
+// - lines starting with # are comments
+func splitResponseFile(content string) (args []string, err error) {
+	var b strings.Builder
+	var inArg bool
+	var quote rune
+	var escaped bool
+	lineStart := true
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case escaped:
+			b.WriteRune(ch)
+			escaped = false
+		case quote == '\'':
+			if ch == '\'' {
+				quote = 0
+				continue
+			}
+			b.WriteRune(ch)
+		case quote == '"':
+			switch {
+			case ch == '"':
+				quote = 0
+			case ch == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				b.WriteRune(runes[i+1])
+				i++
+			default:
+				b.WriteRune(ch)
+			}
+		case ch == '#' && lineStart && !inArg:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		case ch == '\\':
+			escaped = true
+			inArg = true
+		case ch == '\'' || ch == '"':
+			quote = ch
+			inArg = true
+		default:
+			b.WriteRune(ch)
+			inArg = true
+		}
+
+		// a comment ends at the new line, where the index is left
+		if i < len(runes) {
+			ch = runes[i]
+		}
+		switch ch {
+		case '\n':
+			lineStart = true
+		case ' ', '\t', '\r':
+		default:
+			lineStart = false
+		}
+	}
+	if quote != 0 {
+		args = nil
+		err = fmt.Errorf("unterminated %c quote", quote)
+		return
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return
+}
+
+// expandEnv is the function that replaces the ${VAR} references in the args by the value of the environment variable.
+// - ${VAR:-default} is replaced by default if the variable is not defined or empty
+// - $${ is the literal ${
+// - an undefined variable without default is an error
+func expandEnv(args []string) (r []string, err error) {
+	r = make([]string, 0, len(args))
+	for _, arg := range args {
+		var b strings.Builder
+		for {
+			i := strings.Index(arg, "${")
+			if i == -1 {
+				b.WriteString(arg)
+				break
+			}
+			// escaped
+			if i > 0 && arg[i-1] == '$' {
+				b.WriteString(arg[:i])
+				b.WriteString("{")
+				arg = arg[i+2:]
+				continue
+			}
+			end := strings.Index(arg[i:], "}")
+			if end == -1 {
+				b.WriteString(arg)
+				break
+			}
+			b.WriteString(arg[:i])
+
+			// variable
+			name, def, hasDefault := strings.Cut(arg[i+2:i+end], ":-")
+			value, ok := os.LookupEnv(name)
+			switch {
+			case ok && value != "":
+			case hasDefault:
+				value = def
+			case !ok:
+				r = nil
+				err = fmt.Errorf("%w: %s", ErrEnvUndefined, name)
+				return
+			}
+			b.WriteString(value)
+			arg = arg[i+end+1:]
+		}
+		r = append(r, b.String())
+	}
+	return
+}
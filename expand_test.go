@@ -0,0 +1,126 @@
+package gocli
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitResponseFile tests the function splitResponseFile.
+func TestSplitResponseFile(t *testing.T) {
+	t.Run("success - case 01: args separated by white space and new lines", func(t *testing.T) {
+		// arrange
+		content := "--ids 1\n2\t3\r\n  4\n"
+
+		// act
+		args, err := splitResponseFile(content)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"--ids", "1", "2", "3", "4"}, args)
+	})
+
+	t.Run("success - case 02: quotes, escapes and comments", func(t *testing.T) {
+		// arrange
+		content := "# ids of the users\n" +
+			"--name 'John \"Doe\"' --title \"Mr \\\"J\\\"\"\n" +
+			"  # indented comment\n" +
+			"path\\ with\\ spaces a#b\n"
+
+		// act
+		args, err := splitResponseFile(content)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"--name", `John "Doe"`, "--title", `Mr "J"`, "path with spaces", "a#b"}, args)
+	})
+
+	t.Run("failure - case 01: unterminated quote", func(t *testing.T) {
+		// arrange
+		content := "--name 'John"
+
+		// act
+		args, err := splitResponseFile(content)
+
+		// assert
+		require.EqualError(t, err, "unterminated ' quote")
+		require.Nil(t, args)
+	})
+}
+
+// TestExpandResponseFiles tests the function expandResponseFiles.
+func TestExpandResponseFiles(t *testing.T) {
+	t.Run("success - case 01: nested response files and escaped @", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		ids := filepath.Join(dir, "ids.txt")
+		require.NoError(t, os.WriteFile(ids, []byte("1 2\n3\n"), 0o644))
+		args := filepath.Join(dir, "args.txt")
+		require.NoError(t, os.WriteFile(args, []byte("--ids\n@"+ids+"\n"), 0o644))
+
+		// act
+		r, err := expandResponseFiles([]string{"users", "delete", "@" + args, "@@admin", "@"})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"users", "delete", "--ids", "1", "2", "3", "@admin", "@"}, r)
+	})
+
+	t.Run("failure - case 01: file not found", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "missing.txt")
+
+		// act
+		r, err := expandResponseFiles([]string{"@" + path})
+
+		// assert
+		require.ErrorIs(t, err, ErrResponseFile)
+		require.Nil(t, r)
+	})
+
+	t.Run("failure - case 02: nested too deep", func(t *testing.T) {
+		// arrange
+		// - each file includes the next one
+		dir := t.TempDir()
+		for i := 0; i <= MaxResponseFileDepth; i++ {
+			next := "@" + filepath.Join(dir, strconv.Itoa(i+1))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, strconv.Itoa(i)), []byte(next), 0o644))
+		}
+
+		// act
+		r, err := expandResponseFiles([]string{"@" + filepath.Join(dir, "0")})
+
+		// assert
+		require.ErrorIs(t, err, ErrResponseFileDepth)
+		require.Nil(t, r)
+	})
+}
+
+// TestExpandEnv tests the function expandEnv.
+func TestExpandEnv(t *testing.T) {
+	t.Run("success - case 01: variables, defaults and escapes", func(t *testing.T) {
+		// arrange
+		t.Setenv("GOCLI_TEST_REGION", "eu")
+		t.Setenv("GOCLI_TEST_EMPTY", "")
+
+		// act
+		r, err := expandEnv([]string{"--region", "${GOCLI_TEST_REGION}-west", "${GOCLI_TEST_EMPTY:-dev}", "$${GOCLI_TEST_REGION}", "${unclosed"})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"--region", "eu-west", "dev", "${GOCLI_TEST_REGION}", "${unclosed"}, r)
+	})
+
+	t.Run("failure - case 01: undefined variable", func(t *testing.T) {
+		// act
+		r, err := expandEnv([]string{"${GOCLI_TEST_UNDEFINED}"})
+
+		// assert
+		require.ErrorIs(t, err, ErrEnvUndefined)
+		require.EqualError(t, err, "environment variable is not defined: GOCLI_TEST_UNDEFINED")
+		require.Nil(t, r)
+	})
+}
@@ -0,0 +1,371 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrFlagRequired is the error that returns when a required flag is not given.
+	ErrFlagRequired = errors.New("flag is required")
+	// ErrFlagInvalidType is the error that returns when the value of a flag does not match its type.
+	ErrFlagInvalidType = errors.New("flag has an invalid type")
+	// ErrFlagInvalidValue is the error that returns when the value of a flag breaks a validation rule.
+	ErrFlagInvalidValue = errors.New("flag has an invalid value")
+)
+
+// FlagType is the type that represents the type of the value of a flag.
+type FlagType string
+
+const (
+	// FlagTypeString is a text value, the default type.
+	FlagTypeString FlagType = "string"
+	// FlagTypeInt is an integer value, e.g. 10.
+	FlagTypeInt FlagType = "int"
+	// FlagTypeFloat is a decimal value, e.g. 0.5.
+	FlagTypeFloat FlagType = "float"
+	// FlagTypeBool is a boolean value, e.g. true.
+	FlagTypeBool FlagType = "bool"
+	// FlagTypeDuration is a duration value, e.g. 1m30s.
+	FlagTypeDuration FlagType = "duration"
+)
+
+// FlagExists is the type that represents the kind of path that the value of a flag must exist as.
+type FlagExists string
+
+const (
+	// FlagExistsFile requires the value to be the path of an existing file.
+	FlagExistsFile FlagExists = "file"
+	// FlagExistsDir requires the value to be the path of an existing dir.
+	FlagExistsDir FlagExists = "dir"
+)
+
+// FlagRule is the type that represents the validation rule a flag failed.
+type FlagRule string
+
+const (
+	// FlagRuleRequired is the rule of a required flag.
+	FlagRuleRequired FlagRule = "required"
+	// FlagRuleType is the rule of the type of a flag.
+	FlagRuleType FlagRule = "type"
+	// FlagRuleEnum is the rule of the allowed values of a flag.
+	FlagRuleEnum FlagRule = "enum"
+	// FlagRuleMin is the rule of the minimum of a numeric flag.
+	FlagRuleMin FlagRule = "min"
+	// FlagRuleMax is the rule of the maximum of a numeric flag.
+	FlagRuleMax FlagRule = "max"
+	// FlagRulePattern is the rule of the regexp pattern of a flag.
+	FlagRulePattern FlagRule = "pattern"
+	// FlagRuleExists is the rule of the existence of the path of a flag.
+	FlagRuleExists FlagRule = "exists"
+	// FlagRuleValidate is the rule of the custom validator of a flag.
+	FlagRuleValidate FlagRule = "validate"
+)
+
+// FlagError is the struct that represents a flag that failed a validation rule.
+// - for a flag group, the rule is the kind of the group
+type FlagError struct {
+	// Command is the command chain of the flag, e.g. "db migrate".
+	Command string
+	// Flag is the name of the flag, without dashes, or the names of a flag group separated by commas.
+	Flag string
+	// Rule is the rule that failed.
+	Rule FlagRule
+	// Err is the error of the rule.
+	Err error
+}
+
+// Error is the method that returns the message of the error, prefixed by the command chain.
+func (e *FlagError) Error() (msg string) {
+	msg = e.Command + ": " + e.Err.Error()
+	return
+}
+
+// Unwrap is the method that returns the wrapped error.
+func (e *FlagError) Unwrap() (err error) {
+	err = e.Err
+	return
+}
+
+// Flag is the struct that represents the specification of a flag.
+type Flag struct {
+	// Name is the name of the flag, without dashes.
+	Name string
+	// Description is the description of the flag.
+	Description string
+	// Type is the type of the value of the flag.
+	// - default: string, or the type of the Value
+	Type FlagType
+	// Value is the constructor of the custom type of the value of the flag, nil for the built-in types.
+	// - a new Value is set with the text of the flag on each run, and given to the handler in Input.Flags
+	// - e.g. gocli.NewByteSize, or a flag.Value of the standard library through NewStdValue
+	Value func() Value
+	// Default is the value of the flag when it is not given.
+	Default string
+	// Required is the flag that indicates if the flag must be given.
+	Required bool
+	// Hidden is the flag that indicates if the flag is left out of help and completion.
+	Hidden bool
+	// Deprecated is the deprecation of the flag, nil if it is not deprecated.
+	Deprecated *Deprecated
+	// Secret is the flag that indicates if the value of the flag is a secret, e.g. a token.
+	// - its value is shown as *** in help, logs, manifests and errors
+	// - its value can also be read from a file with --<name>-file, or from stdin with --<name>-file /dev/stdin
+	Secret bool
+
+	// Enum are the allowed values of the flag, also used for help and completion.
+	Enum []string
+	// Min is the minimum of the value of a numeric flag, nil if there is none.
+	// - durations are compared in seconds and byte sizes in bytes, e.g. 10MiB is 10485760
+	Min *float64
+	// Max is the maximum of the value of a numeric flag, nil if there is none.
+	// - durations are compared in seconds and byte sizes in bytes
+	Max *float64
+	// Pattern is the regexp pattern the value of the flag must match.
+	Pattern string
+	// Exists is the kind of path the value of the flag must exist as: a file or a dir.
+	Exists FlagExists
+	// Validate is a custom validator of the value of the flag.
+	// - the value is given typed after the type of the flag, e.g. an int64 for an int flag
+	Validate func(value any) error
+}
+
+// FlagType is the method that returns the type of the flag, string if it is not set.
+// - for a flag with a Value, it is the type of the Value
+func (f Flag) FlagType() (t FlagType) {
+	t = f.Type
+	switch {
+	case t != "":
+	case f.Value != nil:
+		t = FlagType(f.Value().Type())
+	default:
+		t = FlagTypeString
+	}
+	return
+}
+
+// ValueHelp is the method that returns the format of the custom type of the flag, empty for the built-in types.
+// - it is the help of the Value, or the name of its type
+func (f Flag) ValueHelp() (h string) {
+	if f.Value == nil {
+		return
+	}
+	v := f.Value()
+	h = v.Type()
+	if vh, ok := v.(ValueHelper); ok {
+		h = vh.Help()
+	}
+	return
+}
+
+// DefaultText is the method that returns the default of the flag as shown to the user.
+// - the default of a secret flag is redacted
+func (f Flag) DefaultText() (d string) {
+	d = f.Default
+	if f.Secret && d != "" {
+		d = Redacted
+	}
+	return
+}
+
+// Describe is the method that returns the description of the flag followed by its notes, as shown to the user.
+// - the notes are, in order: required, enum, value help, default and secret, e.g. "port (required) (default: 8080)"
+// - note formats each note, e.g. to dim it, nil keeps it as is
+// - the default is left out if withDefault is false, e.g. when it is shown in a column of its own
+// - the deprecation is not included, since its format depends on the output
+func (f Flag) Describe(note func(string) string, withDefault bool) (d string) {
+	if note == nil {
+		note = func(s string) string { return s }
+	}
+
+	var notes []string
+	if f.Required {
+		notes = append(notes, "(required)")
+	}
+	if len(f.Enum) > 0 {
+		notes = append(notes, "(one of: "+strings.Join(f.Enum, ", ")+")")
+	}
+	if h := f.ValueHelp(); h != "" {
+		notes = append(notes, "("+h+")")
+	}
+	if withDefault && f.Default != "" {
+		notes = append(notes, "(default: "+f.DefaultText()+")")
+	}
+	if f.Secret {
+		notes = append(notes, "(secret, or --"+f.Name+"-file)")
+	}
+
+	d = f.Description
+	for _, n := range notes {
+		d = strings.TrimSpace(d + " " + note(n))
+	}
+	return
+}
+
+// Flags is the type that represents a list of flags.
+type Flags []Flag
+
+// Find is the method that finds a flag by name.
+func (f Flags) Find(name string) (flag Flag, ok bool) {
+	for _, fl := range f {
+		if fl.Name == name {
+			flag = fl
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// applyFlagDefaults is the function that sets the default of the flags that were not given.
+func applyFlagDefaults(specs Flags, flags map[string]any) (r map[string]any) {
+	r = flags
+	for _, f := range specs {
+		if f.Default == "" {
+			continue
+		}
+		if _, ok := r[f.Name]; ok {
+			continue
+		}
+		if r == nil {
+			r = make(map[string]any)
+		}
+		r[f.Name] = f.Default
+	}
+	return
+}
+
+// validateFlags is the function that checks the flags against their specs.
+// - required flags must be given and the values must match their types
+// - the values must pass the validation rules: enum, min, max, pattern, exists and the custom validator
+// - all the failures are returned at once, joined, each one as a FlagError
+func validateFlags(command string, specs Flags, flags map[string]any) (err error) {
+	var errs []error
+	fail := func(f Flag, rule FlagRule, e error) {
+		errs = append(errs, &FlagError{Command: command, Flag: f.Name, Rule: rule, Err: e})
+	}
+
+	for _, f := range specs {
+		value, ok := flags[f.Name]
+		if !ok {
+			if f.Required {
+				fail(f, FlagRuleRequired, fmt.Errorf("%w: --%s", ErrFlagRequired, f.Name))
+			}
+			continue
+		}
+
+		// shown value, redacted for secrets
+		text := fmt.Sprint(value)
+		shown := strconv.Quote(text)
+		if f.Secret {
+			shown = strconv.Quote(Redacted)
+		}
+
+		// type
+		typed, perr := parseFlagValue(f.FlagType(), text)
+		if f.Value != nil {
+			v := f.Value()
+			perr = v.Set(text)
+			typed = v
+		}
+		if perr != nil {
+			fail(f, FlagRuleType, fmt.Errorf("%w: --%s: expected %s, got %s", ErrFlagInvalidType, f.Name, f.FlagType(), shown))
+			continue
+		}
+
+		// rules
+		invalid := func(rule FlagRule, format string, args ...any) {
+			fail(f, rule, fmt.Errorf("%w: --%s: %s: %s", ErrFlagInvalidValue, f.Name, rule, fmt.Sprintf(format, args...)))
+		}
+		if len(f.Enum) > 0 && !contains(f.Enum, text) {
+			invalid(FlagRuleEnum, "%s is not one of %s", shown, strings.Join(f.Enum, ", "))
+		}
+		if f.Min != nil || f.Max != nil {
+			n, isNumber := flagNumber(typed, text)
+			switch {
+			case !isNumber:
+				invalid(FlagRuleMin, "%s is not a number", shown)
+			case f.Min != nil && n < *f.Min:
+				invalid(FlagRuleMin, "%s is less than %s", shown, strconv.FormatFloat(*f.Min, 'f', -1, 64))
+			case f.Max != nil && n > *f.Max:
+				invalid(FlagRuleMax, "%s is greater than %s", shown, strconv.FormatFloat(*f.Max, 'f', -1, 64))
+			}
+		}
+		if f.Pattern != "" {
+			re, rerr := regexp.Compile(f.Pattern)
+			switch {
+			case rerr != nil:
+				invalid(FlagRulePattern, "invalid pattern %q: %s", f.Pattern, rerr)
+			case !re.MatchString(text):
+				invalid(FlagRulePattern, "%s does not match %s", shown, f.Pattern)
+			}
+		}
+		if f.Exists != "" {
+			info, serr := os.Stat(text)
+			switch {
+			case serr != nil:
+				invalid(FlagRuleExists, "%s does not exist", shown)
+			case f.Exists == FlagExistsFile && info.IsDir():
+				invalid(FlagRuleExists, "%s is not a file", shown)
+			case f.Exists == FlagExistsDir && !info.IsDir():
+				invalid(FlagRuleExists, "%s is not a dir", shown)
+			}
+		}
+		if f.Validate != nil {
+			if verr := f.Validate(typed); verr != nil {
+				invalid(FlagRuleValidate, "%s", verr)
+			}
+		}
+	}
+
+	err = errors.Join(errs...)
+	return
+}
+
+// flagNumber is the function that returns the number of a parsed flag value, to check its minimum and maximum.
+// - durations are in seconds and byte sizes in bytes
+// - other values are parsed from their text, e.g. a Value wrapping a std flag
+func flagNumber(typed any, text string) (n float64, ok bool) {
+	switch v := typed.(type) {
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	case time.Duration:
+		n = v.Seconds()
+	case *ByteSize:
+		n = float64(*v)
+	default:
+		var err error
+		n, err = strconv.ParseFloat(text, 64)
+		if err != nil {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// parseFlagValue is the function that parses a value after a type.
+// - custom types are parsed by their Value, see validateFlags
+// - int values are int64, float values are float64, bool values are bool and duration values are time.Duration
+func parseFlagValue(t FlagType, value string) (v any, err error) {
+	switch t {
+	case FlagTypeInt:
+		v, err = strconv.ParseInt(value, 10, 64)
+	case FlagTypeFloat:
+		v, err = strconv.ParseFloat(value, 64)
+	case FlagTypeBool:
+		v, err = strconv.ParseBool(value)
+	case FlagTypeDuration:
+		v, err = time.ParseDuration(value)
+	default:
+		v = value
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrFlagsExclusive is the error that returns when more than one flag of a mutually exclusive group is given.
+	ErrFlagsExclusive = errors.New("flags are mutually exclusive")
+	// ErrFlagsRequiredTogether is the error that returns when only some flags of a required together group are given.
+	ErrFlagsRequiredTogether = errors.New("flags are required together")
+	// ErrFlagsOneRequired is the error that returns when no flag of an at least one or exactly one group is given.
+	ErrFlagsOneRequired = errors.New("one of the flags is required")
+)
+
+// FlagGroupKind is the type that represents the relationship between the flags of a group.
+type FlagGroupKind string
+
+const (
+	// FlagGroupExclusive allows at most one of the flags, e.g. --file or --stdin, not both.
+	FlagGroupExclusive FlagGroupKind = "exclusive"
+	// FlagGroupTogether requires all the flags if any is given, e.g. --user requires --password.
+	FlagGroupTogether FlagGroupKind = "together"
+	// FlagGroupAtLeastOne requires at least one of the flags.
+	FlagGroupAtLeastOne FlagGroupKind = "at_least_one"
+	// FlagGroupExactlyOne requires exactly one of the flags.
+	FlagGroupExactlyOne FlagGroupKind = "exactly_one"
+)
+
+// FlagGroup is the struct that represents a relationship between flags of a command.
+type FlagGroup struct {
+	// Kind is the relationship between the flags.
+	Kind FlagGroupKind `json:"kind"`
+	// Flags are the names of the flags, without dashes.
+	Flags []string `json:"flags"`
+}
+
+// Description is the method that returns the relationship of the group as shown in help.
+func (g FlagGroup) Description() (d string) {
+	switch g.Kind {
+	case FlagGroupExclusive:
+		d = "mutually exclusive"
+	case FlagGroupTogether:
+		d = "required together"
+	case FlagGroupAtLeastOne:
+		d = "at least one required"
+	case FlagGroupExactlyOne:
+		d = "exactly one required"
+	default:
+		d = string(g.Kind)
+	}
+	return
+}
+
+// Names is the method that returns the flags of the group with dashes, e.g. "--file, --stdin".
+func (g FlagGroup) Names() (names string) {
+	list := make([]string, 0, len(g.Flags))
+	for _, name := range g.Flags {
+		list = append(list, "--"+name)
+	}
+	names = strings.Join(list, ", ")
+	return
+}
+
+// validateFlagGroups is the function that checks the flags given against the groups of a command.
+// - the flags given are the ones of the command line, before the defaults are applied
+// - all the failures are returned at once, joined, each one as a FlagError
+func validateFlagGroups(command string, groups []FlagGroup, given map[string]any) (err error) {
+	var errs []error
+	for _, g := range groups {
+		var present, missing []string
+		for _, name := range g.Flags {
+			if _, ok := given[name]; ok {
+				present = append(present, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+
+		var gerr error
+		switch g.Kind {
+		case FlagGroupExclusive:
+			if len(present) > 1 {
+				gerr = fmt.Errorf("%w: %s", ErrFlagsExclusive, FlagGroup{Flags: present}.Names())
+			}
+		case FlagGroupTogether:
+			if len(present) > 0 && len(missing) > 0 {
+				gerr = fmt.Errorf("%w: %s (missing %s)", ErrFlagsRequiredTogether, g.Names(), FlagGroup{Flags: missing}.Names())
+			}
+		case FlagGroupAtLeastOne:
+			if len(present) == 0 {
+				gerr = fmt.Errorf("%w: %s", ErrFlagsOneRequired, g.Names())
+			}
+		case FlagGroupExactlyOne:
+			switch {
+			case len(present) == 0:
+				gerr = fmt.Errorf("%w: %s", ErrFlagsOneRequired, g.Names())
+			case len(present) > 1:
+				gerr = fmt.Errorf("%w: %s", ErrFlagsExclusive, FlagGroup{Flags: present}.Names())
+			}
+		}
+		if gerr != nil {
+			errs = append(errs, &FlagError{Command: command, Flag: strings.Join(g.Flags, ","), Rule: FlagRule(g.Kind), Err: gerr})
+		}
+	}
+
+	err = errors.Join(errs...)
+	return
+}
@@ -0,0 +1,44 @@
+package gocli_test
+
+import (
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFlag_Describe tests the method Describe of the Flag type.
+func TestFlag_Describe(t *testing.T) {
+	t.Run("success - case 01: description without notes", func(t *testing.T) {
+		// arrange
+		f := gocli.Flag{Name: "env", Description: "environment"}
+
+		// act
+		d := f.Describe(nil, true)
+
+		// assert
+		require.Equal(t, "environment", d)
+	})
+
+	t.Run("success - case 02: notes in order", func(t *testing.T) {
+		// arrange
+		f := gocli.Flag{Name: "token", Description: "api token", Required: true, Enum: []string{"a", "b"}, Default: "a", Secret: true}
+
+		// act
+		d := f.Describe(nil, true)
+
+		// assert
+		require.Equal(t, "api token (required) (one of: a, b) (default: ***) (secret, or --token-file)", d)
+	})
+
+	t.Run("success - case 03: notes are formatted and the default is left out", func(t *testing.T) {
+		// arrange
+		f := gocli.Flag{Name: "env", Required: true, Default: "dev"}
+
+		// act
+		d := f.Describe(func(s string) string { return "<" + s + ">" }, false)
+
+		// assert
+		require.Equal(t, "<(required)>", d)
+	})
+}
@@ -0,0 +1,109 @@
+package gocli
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"time"
+)
+
+// NewFlagSetCommand is the function that adapts a flag.FlagSet of the standard library and its run function to a command.
+// - newFlagSet returns a new flag set on each call, so the values are not kept between runs, e.g. with ServeDaemon
+// - the name of the command is the name of the flag set, the flags are imported as its specification
+// - the args after the command are given as is to the flag set, then run is called with it, e.g. to read fs.Args()
+// - the flags given are checked against the specification of the command at run time, so the rules set on it apply,
+// e.g. cmd.Flags[0].Enum
+// - the flag set is switched to flag.ContinueOnError, its errors and usage are written to the error writer
+func NewFlagSetCommand(newFlagSet func() *flag.FlagSet, description string, run func(fs *flag.FlagSet, i Input) (err error)) (cmd Command) {
+	// flags
+	fs := newFlagSet()
+	var flags Flags
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, flagSetFlag(f))
+	})
+
+	cmd = Command{
+		Name:        fs.Name(),
+		Description: description,
+		Flags:       flags,
+		RawArgs:     true,
+		Handler: func(i Input) (err error) {
+			// args after the chain and the command
+			args := i.Args
+			if n := len(i.CommandInput.Chain) + 1; len(args) >= n {
+				args = args[n:]
+			}
+
+			// parse
+			fs := newFlagSet()
+			fs.Init(fs.Name(), flag.ContinueOnError)
+			if i.ErrWriter != nil {
+				fs.SetOutput(i.ErrWriter)
+			}
+			err = fs.Parse(args)
+			if errors.Is(err, flag.ErrHelp) {
+				err = nil
+				return
+			}
+			if err != nil {
+				// the error was already written by the flag set, with its usage
+				err = &ExitError{Code: 2}
+				return
+			}
+
+			// validate
+			// - against the specification of the command resolved for the run, so the rules set on cmd.Flags
+			// after this function returns are checked, the imported flags are only used without it
+			specs := i.specs
+			if specs == nil {
+				specs = flags
+			}
+			i.Flags = make(map[string]any)
+			fs.Visit(func(f *flag.Flag) {
+				i.Flags[f.Name] = f.Value.String()
+			})
+			command := strings.Join(append(append([]string{}, i.CommandInput.Chain...), i.CommandInput.Command), " ")
+			err = validateFlags(command, specs, i.Flags)
+			if err != nil {
+				return
+			}
+
+			// run
+			err = run(fs, i)
+			return
+		},
+	}
+	return
+}
+
+// flagSetFlag is the function that returns the specification of a flag of a flag.FlagSet.
+// - the type is taken from the value of the flag, the other types are strings, since any text is given to the flag set
+// - zero defaults are left out, like the flag package does in its usage
+func flagSetFlag(f *flag.Flag) (spec Flag) {
+	spec = Flag{Name: f.Name, Description: f.Usage}
+	switch f.DefValue {
+	case "", "0", "false", "0s":
+	default:
+		spec.Default = f.DefValue
+	}
+
+	// type
+	var value any = f.Value
+	if getter, ok := f.Value.(flag.Getter); ok {
+		value = getter.Get()
+	}
+	switch value.(type) {
+	case int, int64, uint, uint64:
+		spec.Type = FlagTypeInt
+	case float64:
+		spec.Type = FlagTypeFloat
+	case bool:
+		spec.Type = FlagTypeBool
+	case time.Duration:
+		spec.Type = FlagTypeDuration
+	default:
+		// - strings and the values parsed by the flag set itself, e.g. a flag.Func
+		spec.Type = FlagTypeString
+	}
+	return
+}
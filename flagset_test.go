@@ -0,0 +1,195 @@
+package gocli_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// listValue is a flag.Value of the standard library used in the tests, collecting the values given.
+type listValue []string
+
+// Set is the method that appends a value.
+func (l *listValue) Set(value string) (err error) {
+	*l = append(*l, value)
+	return
+}
+
+// String is the method that returns the values separated by commas.
+func (l *listValue) String() (s string) {
+	s = strings.Join(*l, ",")
+	return
+}
+
+// TestNewFlagSetCommand tests the function NewFlagSetCommand.
+func TestNewFlagSetCommand(t *testing.T) {
+	// newFlagSet is the function that returns the flag set of the lint tool used in the tests
+	newFlagSet := func() (fs *flag.FlagSet) {
+		fs = flag.NewFlagSet("lint", flag.ExitOnError)
+		fs.Bool("strict", false, "fails on warnings")
+		fs.Int("jobs", 4, "number of workers")
+		fs.Duration("timeout", time.Minute, "timeout of the run")
+		fs.Var(&listValue{}, "tag", "build tag")
+		fs.BoolFunc("trace", "traces the run", func(string) (err error) { return })
+		return
+	}
+
+	t.Run("success - case 01: flags are imported as the specification", func(t *testing.T) {
+		// act
+		cmd := gocli.NewFlagSetCommand(newFlagSet, "lints the code", func(fs *flag.FlagSet, i gocli.Input) (err error) { return })
+
+		// assert
+		require.Equal(t, "lint", cmd.Name)
+		require.Equal(t, "lints the code", cmd.Description)
+		require.True(t, cmd.RawArgs)
+		require.Len(t, cmd.Flags, 5)
+		require.Equal(t, gocli.Flag{Name: "jobs", Description: "number of workers", Type: gocli.FlagTypeInt, Default: "4"}, cmd.Flags[0])
+		require.Equal(t, gocli.Flag{Name: "strict", Description: "fails on warnings", Type: gocli.FlagTypeBool}, cmd.Flags[1])
+		require.Equal(t, gocli.Flag{Name: "tag", Description: "build tag", Type: gocli.FlagTypeString}, cmd.Flags[2])
+		require.Equal(t, gocli.Flag{Name: "timeout", Description: "timeout of the run", Type: gocli.FlagTypeDuration, Default: "1m0s"}, cmd.Flags[3])
+		require.Equal(t, "trace", cmd.Flags[4].Name)
+		require.Equal(t, gocli.FlagTypeString, cmd.Flags[4].Type)
+		require.Nil(t, cmd.Flags[4].Value)
+	})
+
+	t.Run("success - case 02: args are given to the flag set within a group", func(t *testing.T) {
+		// arrange
+		// - commander
+		var strict bool
+		var jobs int
+		var tags listValue
+		var args []string
+		cm := gocli.NewCommanderManager("app", "app description")
+		tools := cm.Group("tools", "tools")
+		tools.AddCommand(gocli.NewFlagSetCommand(newFlagSet, "lints the code", func(fs *flag.FlagSet, i gocli.Input) (err error) {
+			strict = fs.Lookup("strict").Value.(flag.Getter).Get().(bool)
+			jobs = fs.Lookup("jobs").Value.(flag.Getter).Get().(int)
+			tags = *fs.Lookup("tag").Value.(*listValue)
+			args = fs.Args()
+			return
+		}))
+		// - cli
+		os.Args = []string{"app.exe", "tools", "lint", "-strict", "-jobs", "8", "--tag", "a", "-tag=b", "-trace", "main.go"}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, strict)
+		require.Equal(t, 8, jobs)
+		require.Equal(t, listValue{"a", "b"}, tags)
+		require.Equal(t, []string{"main.go"}, args)
+	})
+
+	t.Run("success - case 03: values are not kept between runs", func(t *testing.T) {
+		// arrange
+		var tags []listValue
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.NewFlagSetCommand(newFlagSet, "lints the code", func(fs *flag.FlagSet, i gocli.Input) (err error) {
+			tags = append(tags, *fs.Lookup("tag").Value.(*listValue))
+			return
+		}))
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+
+		// act
+		os.Args = []string{"app.exe", "lint", "-tag", "a"}
+		errFirst := cli.Run()
+		os.Args = []string{"app.exe", "lint", "-tag", "b"}
+		errSecond := cli.Run()
+
+		// assert
+		require.NoError(t, errFirst)
+		require.NoError(t, errSecond)
+		require.Equal(t, []listValue{{"a"}, {"b"}}, tags)
+	})
+
+	t.Run("failure - case 01: invalid value is reported by the flag set", func(t *testing.T) {
+		// arrange
+		// - commander
+		var called bool
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.NewFlagSetCommand(newFlagSet, "lints the code", func(fs *flag.FlagSet, i gocli.Input) (err error) {
+			called = true
+			return
+		}))
+		// - cli
+		os.Args = []string{"app.exe", "lint", "-jobs", "many"}
+		ew := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = ew
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Equal(t, 2, gocli.ExitCode(err))
+		require.False(t, called)
+		require.Contains(t, ew.String(), "invalid value \"many\" for flag -jobs")
+		require.Contains(t, ew.String(), "Usage of lint:")
+	})
+
+	t.Run("failure - case 02: rules of the specification are checked", func(t *testing.T) {
+		// arrange
+		// - commander
+		var called bool
+		max := 8.0
+		cmd := gocli.NewFlagSetCommand(newFlagSet, "lints the code", func(fs *flag.FlagSet, i gocli.Input) (err error) {
+			called = true
+			return
+		})
+		cmd.Flags[0].Max = &max
+		cmd.Flags[2].Enum = []string{"unit", "e2e"}
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(cmd)
+		// - cli
+		os.Args = []string{"app.exe", "lint", "-jobs", "16", "-tag", "slow"}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = &bytes.Buffer{}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagInvalidValue)
+		require.EqualError(t, err, strings.Join([]string{
+			`lint: flag has an invalid value: --jobs: max: "16" is greater than 8`,
+			`lint: flag has an invalid value: --tag: enum: "slow" is not one of unit, e2e`,
+		}, "\n"))
+		require.False(t, called)
+	})
+	t.Run("failure - case 03: rules set on a copy of the specification are checked", func(t *testing.T) {
+		// arrange
+		// - commander
+		var called bool
+		max := 8.0
+		cmd := gocli.NewFlagSetCommand(newFlagSet, "lints the code", func(fs *flag.FlagSet, i gocli.Input) (err error) {
+			called = true
+			return
+		})
+		cmd.Flags = append(gocli.Flags{}, cmd.Flags...)
+		cmd.Flags[0].Max = &max
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(cmd)
+		// - cli
+		os.Args = []string{"app.exe", "lint", "-jobs", "16"}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = &bytes.Buffer{}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagInvalidValue)
+		require.EqualError(t, err, `lint: flag has an invalid value: --jobs: max: "16" is greater than 8`)
+		require.False(t, called)
+	})
+}
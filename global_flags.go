@@ -0,0 +1,158 @@
+package gocli
+
+import (
+	"slices"
+	"strings"
+)
+
+// extractFlag is the function that extracts a global flag with a value from the args.
+// - it accepts both forms: `--name value` and `--name=value`
+// - it returns the args without the flag, so the parser never sees it
+// - if the flag is repeated, the last value wins
+func extractFlag(args []string, name string) (value string, ok bool, rest []string) {
+	rest = make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// form: --name=value
+		if strings.HasPrefix(arg, name+"=") {
+			value = strings.TrimPrefix(arg, name+"=")
+			ok = true
+			continue
+		}
+
+		// form: --name value
+		if arg == name && i+1 < len(args) {
+			value = args[i+1]
+			ok = true
+			i++
+			continue
+		}
+
+		rest = append(rest, arg)
+	}
+	return
+}
+
+// extractSwitch is the function that extracts a global switch (a flag without value) from the args.
+// - any of the names matches, e.g. "-h" and "--help"
+func extractSwitch(args []string, names ...string) (ok bool, rest []string) {
+	rest = make([]string, 0, len(args))
+	for _, arg := range args {
+		var match bool
+		for _, name := range names {
+			if arg == name {
+				match = true
+				break
+			}
+		}
+		if match {
+			ok = true
+			continue
+		}
+
+		rest = append(rest, arg)
+	}
+	return
+}
+
+// chainWords is the function that returns the words of the args, skipping the flags with their values and the options.
+// - it is used to know the command chain when flags are given within it, e.g. `app --profile x db --help`
+func chainWords(args []string) (words []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+			words = append(words, arg)
+		case patternOption.MatchString(arg):
+		default:
+			i++
+		}
+	}
+	return
+}
+
+// leadingWords is the function that returns the leading args that are not flags nor options.
+// - it is used to know the command chain when the args are not parsed (e.g. commands with raw args)
+func leadingWords(args []string) (words []string) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		words = append(words, arg)
+	}
+	return
+}
+
+// globalSwitches are the global flags without value, skipped to find the command of the args.
+var globalSwitches = []string{"-v", "-vv", "--quiet", "-h", "--help"}
+
+// globalValueFlags are the global flags with a value.
+var globalValueFlags = []string{"--color", "--output", "--log-level", "--log-format", "--timeout"}
+
+// splitLeadingGlobals is the function that splits the global flags given before the first word of the args from the rest.
+// - it is used to find a command with raw args after them, e.g. `app --color never plugin --flag`
+func splitLeadingGlobals(args []string) (globals, rest []string) {
+	n := 0
+	for n < len(args) {
+		name, _, inline := strings.Cut(args[n], "=")
+		if !slices.Contains(globalSwitches, name) && !slices.Contains(globalValueFlags, name) {
+			break
+		}
+		n++
+		// form: --name value
+		if !inline && slices.Contains(globalValueFlags, name) {
+			n = min(n+1, len(args))
+		}
+	}
+	globals, rest = args[:n:n], args[n:]
+	return
+}
+
+// commandFlags is the method that returns the flags declared by the command of the args, including the persistent ones.
+// - it is used to leave a global flag to a command with a flag of the same name, e.g. its own --color
+func (c CLI) commandFlags(args []string) (flags Flags) {
+	if p, ok := c.commandPath(args); ok {
+		flags = p.Flags()
+	}
+	return
+}
+
+// persistentFlags is the method that returns the names of the persistent flags of the groups of the command of the args.
+// - they are the flags that may be given within the chain, see PersistentParser
+func (c CLI) persistentFlags(args []string) (names map[string]bool) {
+	p, ok := c.commandPath(args)
+	if !ok {
+		return
+	}
+
+	names = make(map[string]bool)
+	for _, m := range p.Managers {
+		for _, f := range m.Flags {
+			names[f.Name] = true
+		}
+	}
+	return
+}
+
+// commandPath is the method that returns the path of the command of the args.
+// - the longest chain of words of the args that is a command wins
+func (c CLI) commandPath(args []string) (p CommandPath, ok bool) {
+	finder, isFinder := c.Commander.(CommandPathFinder)
+	if !isFinder {
+		return
+	}
+
+	_, rest := extractSwitch(args, globalSwitches...)
+	words := chainWords(rest)
+	for n := len(words); n > 0; n-- {
+		var err error
+		p, err = finder.FindCommandPath(words[n-1], words[:n-1]...)
+		if err != nil {
+			continue
+		}
+		ok = true
+		return
+	}
+	return
+}
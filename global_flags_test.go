@@ -0,0 +1,76 @@
+package gocli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestExtractFlag tests the function extractFlag.
+func TestExtractFlag(t *testing.T) {
+	t.Run("success - case 01: flag with separated value", func(t *testing.T) {
+		// arrange
+		args := []string{"cmd1", "--color", "never", "--flag1", "value1"}
+
+		// act
+		value, ok, rest := extractFlag(args, "--color")
+
+		// assert
+		require.True(t, ok)
+		require.Equal(t, "never", value)
+		require.Equal(t, []string{"cmd1", "--flag1", "value1"}, rest)
+	})
+
+	t.Run("success - case 02: flag with joined value", func(t *testing.T) {
+		// arrange
+		args := []string{"cmd1", "--color=never"}
+
+		// act
+		value, ok, rest := extractFlag(args, "--color")
+
+		// assert
+		require.True(t, ok)
+		require.Equal(t, "never", value)
+		require.Equal(t, []string{"cmd1"}, rest)
+	})
+
+	t.Run("success - case 03: flag is not present", func(t *testing.T) {
+		// arrange
+		args := []string{"cmd1", "--colors", "never"}
+
+		// act
+		value, ok, rest := extractFlag(args, "--color")
+
+		// assert
+		require.False(t, ok)
+		require.Equal(t, "", value)
+		require.Equal(t, args, rest)
+	})
+}
+
+// TestExtractSwitch tests the function extractSwitch.
+func TestExtractSwitch(t *testing.T) {
+	t.Run("success - case 01: switch is present", func(t *testing.T) {
+		// arrange
+		args := []string{"db", "-h", "migrate"}
+
+		// act
+		ok, rest := extractSwitch(args, "-h", "--help")
+
+		// assert
+		require.True(t, ok)
+		require.Equal(t, []string{"db", "migrate"}, rest)
+	})
+
+	t.Run("success - case 02: switch is not present", func(t *testing.T) {
+		// arrange
+		args := []string{"db", "migrate"}
+
+		// act
+		ok, rest := extractSwitch(args, "-h", "--help")
+
+		// assert
+		require.False(t, ok)
+		require.Equal(t, args, rest)
+	})
+}
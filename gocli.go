@@ -1,8 +1,16 @@
 package gocli
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 )
 
 // NewCLI is the function that returns a new CLI.
@@ -10,6 +18,13 @@ func NewCLI(p Parser, c Commander) (r CLI) {
 	r = CLI{
 		Parser: p,
 		Commander: c,
+		Reader: os.Stdin,
+		Writer: os.Stdout,
+		ErrWriter: os.Stderr,
+		Color: ColorModeAuto,
+		Output: OutputModeText,
+		LogLevel: slog.LevelWarn,
+		LogFormat: LogFormatText,
 	}
 	return
 }
@@ -22,31 +37,579 @@ type CLI struct {
 	// Commander is the commander of the CLI.
 	// helps finding the command handler from the input.
 	Commander
+
+	// Reader is the reader where the CLI reads the user input from.
+	// - default: os.Stdin
+	Reader io.Reader
+	// Writer is the writer where the CLI writes the output to.
+	// - default: os.Stdout
+	Writer io.Writer
+	// ErrWriter is the writer where the CLI writes the errors to.
+	// - default: os.Stderr
+	ErrWriter io.Writer
+	// Color is the color mode of the output.
+	// - default: auto, it can be overridden with the --color flag
+	Color ColorMode
+	// Output is the output mode of the commands.
+	// - default: text, it can be overridden with the --output flag
+	Output OutputMode
+	// Strict is the flag that turns the warnings about deprecated commands, groups and flags into errors.
+	Strict bool
+	// Version is the version of the app, e.g. "1.2.0".
+	Version string
+	// LogLevel is the minimum level of the logs written to the error writer.
+	// - default: warn, it can be overridden with the -v, -vv, --quiet and --log-level flags
+	LogLevel slog.Level
+	// LogFormat is the format of the logs.
+	// - default: text, it can be overridden with the --log-format flag
+	LogFormat LogFormat
+	// Tracer is the tracer of the phases of a run: parse, resolve and handler.
+	// - default: nil, no spans are emitted
+	Tracer Tracer
+	// CrashDir is the dir where a crash report is written when a command handler panics.
+	// - default: empty, no crash reports are written
+	CrashDir string
+	// ResponseFiles is the flag that enables the expansion of @path args into the args in the file at path.
+	// - default: false
+	// - an expanded arg with white space, e.g. a quoted "a b", is an ErrExpandedArgSpace error for the commands without raw args
+	ResponseFiles bool
+	// ExpandEnv is the flag that enables the interpolation of ${VAR} in the args with the environment variables.
+	// - default: false
+	ExpandEnv bool
+	// Timeout is the time the handlers of the commands without a timeout of their own have to run.
+	// - default: 0, no timeout; the --timeout flag overrides it and the timeout of the command for a run
+	Timeout time.Duration
+	// GracePeriod is the time a handler has to return after its context is cancelled, by a timeout or an interrupt.
+	// - default: 0, DefaultGracePeriod is used
+	GracePeriod time.Duration
+	// MCP is the flag that enables `app __mcp`, which runs the MCP server over stdin and stdout.
+	// - default: false
+	MCP bool
+	// ServeHosts are the hosts accepted in the Host header of the requests of ServeHandler, besides the loopback ones.
+	// - default: empty, only localhost and the loopback addresses are accepted, see ServeHandler
+	ServeHosts []string
 }
 
 // Run is the method that runs the CLI.
+// - the context of the handler is cancelled on interrupt or SIGTERM, then the handler has the grace period to return
 func (c CLI) Run() (err error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = c.RunContext(ctx)
+	return
+}
+
+// RunContext is the method that runs the CLI with a context.
+// - the context is given to the command handler through the Input
+// - when the context is done, the handler has the grace period to return
+func (c CLI) RunContext(ctx context.Context) (err error) {
 	// fetch args
-	// - os.Stdin
-	args := strings.Join(os.Args[1:], " ")
-	
-	// parse the input
-	input, err := c.Parser.Parse(args)
-	if err != nil {
+	// - os.Args
+	err = c.run(ctx, os.Args[1:])
+	return
+}
+
+// runStarter is the interface implemented by the commanders that keep state during a run, e.g. the plugins found.
+type runStarter interface {
+	// startRun clears the state of the previous run.
+	startRun()
+}
+
+// run is the method that runs the CLI with the given args, without the app name.
+func (c CLI) run(ctx context.Context, args []string) (err error) {
+	if r, ok := c.Commander.(runStarter); ok {
+		r.startRun()
+	}
+
+	// completion
+	if len(args) > 0 && args[0] == "__complete" {
+		err = c.complete(args[1:])
 		return
 	}
-	
+	// model context protocol server
+	if c.MCP && len(args) > 0 && args[0] == "__mcp" {
+		err = c.ServeMCP(ctx, c.reader(), c.writer())
+		return
+	}
+
+	// expansion
+	// - response files first, so the args in the files are also interpolated
+	expanded := c.ResponseFiles || c.ExpandEnv
+	if c.ResponseFiles {
+		args, err = expandResponseFiles(args)
+		if err != nil {
+			return
+		}
+	}
+	if c.ExpandEnv {
+		args, err = expandEnv(args)
+		if err != nil {
+			return
+		}
+	}
+
+	// user aliases
+	// - an alias is only expanded as the first arg, and never if a command has its name
+	var alias string
+	var warnings []string
+	if finder, ok := c.Commander.(AliasFinder); ok && len(args) > 0 {
+		if a, found := finder.FindAlias(args[0]); found {
+			switch {
+			case a.Shadowed:
+				warnings = append(warnings, fmt.Sprintf("alias %q is shadowed by a command with the same name, it is ignored", a.Name))
+			case a.Macro():
+				err = c.runMacro(ctx, a, args[1:])
+				return
+			default:
+				var aliasArgs []string
+				aliasArgs, err = a.Args()
+				if err != nil {
+					return
+				}
+				args = append(aliasArgs, args[1:]...)
+				alias = a.Name
+				expanded = true
+			}
+		}
+	}
+	argv := args
+
+	// trace
+	tracer := c.tracer()
+	ctx, span := tracer.Start(ctx, SpanRun)
+	defer func() { endSpan(span, err) }()
+
+	// args of a command with raw args are given to it as is
+	// - global flags are only read before the command
+	var rawArgs []string
+	globals, rest := splitLeadingGlobals(args)
+	rawCommand, isRaw := c.findRawArgsCommand(rest)
+	if isRaw {
+		n := len(globals) + len(rawCommand.Chain) + 1
+		args, rawArgs = args[:n:n], args[n:]
+	}
+
+	// global flags
+	// - a global flag is left to the command if it declares a flag with the same name, e.g. its own --color
+	own := c.commandFlags(args)
+	global := func(name string) (ok bool) {
+		_, declared := own.Find(strings.TrimLeft(name, "-"))
+		ok = !declared
+		return
+	}
+	// - color, also used by the warnings of the run
+	if value, ok, rest := extractFlag(args, "--color"); ok && global("--color") {
+		args = rest
+		c.Color, err = ParseColorMode(value)
+		if err != nil {
+			return
+		}
+	}
+	style := NewStyler(c.writer(), c.Color)
+	c.warn(c.errWriter(), warnings...)
+	// - output
+	output := c.Output
+	if value, ok, rest := extractFlag(args, "--output"); ok && global("--output") {
+		args = rest
+		output, err = ParseOutputMode(value)
+		if err != nil {
+			return
+		}
+	}
+	// - logging
+	level := c.LogLevel
+	if ok, rest := extractSwitch(args, "-v"); ok && global("-v") {
+		args = rest
+		level = slog.LevelInfo
+	}
+	if ok, rest := extractSwitch(args, "-vv"); ok && global("-vv") {
+		args = rest
+		level = slog.LevelDebug
+	}
+	if ok, rest := extractSwitch(args, "--quiet"); ok && global("--quiet") {
+		args = rest
+		level = slog.LevelError
+	}
+	if value, ok, rest := extractFlag(args, "--log-level"); ok && global("--log-level") {
+		args = rest
+		level, err = ParseLogLevel(value)
+		if err != nil {
+			return
+		}
+	}
+	format := c.LogFormat
+	if value, ok, rest := extractFlag(args, "--log-format"); ok && global("--log-format") {
+		args = rest
+		format, err = ParseLogFormat(value)
+		if err != nil {
+			return
+		}
+	}
+	// - timeout
+	var timeout time.Duration
+	if value, ok, rest := extractFlag(args, "--timeout"); ok && global("--timeout") {
+		args = rest
+		timeout, err = ParseTimeout(value)
+		if err != nil {
+			return
+		}
+	}
+	logger := newLogger(c.errWriter(), level, format)
+	if alias != "" {
+		logger.Debug("alias expanded", "alias", alias)
+	}
+	if expanded {
+		logger.Debug("args expanded", "argv", redactArgs(argv, secretFlagNames(c.Commander)))
+	}
+	// - help
+	if ok, rest := extractSwitch(args, "-h", "--help"); ok {
+		err = c.help(style, chainWords(rest)...)
+		return
+	}
+	args = append(args, rawArgs...)
+
+	// parse the input
+	// - a bare invocation runs the root
+	var input Input
+	_, parseSpan := tracer.Start(ctx, SpanParse)
+	switch {
+	case len(args) == 0:
+		logger.Debug("no args, running the root")
+		input.CommandInput = CommandInput{Chain: []string{}}
+	case isRaw:
+		// the parser is skipped, the args are given to the command as is
+		logger.Debug("command with raw args matched", commandAttr(rawCommand))
+		input.CommandInput = rawCommand
+	default:
+		if expanded {
+			err = checkExpandedArgs(args)
+			if err != nil {
+				endSpan(parseSpan, err)
+				return
+			}
+		}
+		logger.Debug("parsing args", "args", redactArgs(args, secretFlagNames(c.Commander)))
+		if pp, ok := c.Parser.(PersistentParser); ok {
+			input, err = pp.ParsePersistent(strings.Join(args, " "), c.persistentFlags(args))
+		} else {
+			input, err = c.Parser.Parse(strings.Join(args, " "))
+		}
+		if err != nil {
+			// commands with raw args accept the args rejected by the parser
+			logger.Debug("args rejected by the parser, looking for a command with raw args", "error", err)
+			var ok bool
+			input.CommandInput, ok = c.findRawArgsCommand(args)
+			if !ok {
+				logger.Debug("no command with raw args matched")
+				endSpan(parseSpan, err)
+				return
+			}
+			logger.Debug("command with raw args matched", commandAttr(input.CommandInput))
+			err = nil
+		}
+	}
+	parseSpan.SetAttributes(inputAttributes(input)...)
+	endSpan(parseSpan, nil)
+	span.SetAttributes(inputAttributes(input)...)
+	input.Logger = logger.With(commandAttr(input.CommandInput))
+	input.Args = args
+	input.Reader = c.reader()
+	input.Writer = c.writer()
+	input.ErrWriter = c.errWriter()
+	input.Interactive = isTerminal(input.Reader)
+	input.Style = style
+	input.Output = output
+	input.Context = ctx
+
+	err = c.execute(input, timeout)
+	return
+}
+
+// execute is the method that finds the handler of the input and runs it.
+// - the reader, writers and context of the input must be already set
+// - timeout is the timeout of the run given with --timeout, 0 if it was not given
+func (c CLI) execute(input Input, timeout time.Duration) (err error) {
+	// logger
+	if input.Logger == nil {
+		w := input.ErrWriter
+		if w == nil {
+			w = c.errWriter()
+		}
+		input.Logger = newLogger(w, c.LogLevel, c.LogFormat).With(commandAttr(input.CommandInput))
+	}
+	logger := input.Logger
+	// trace
+	tracer := c.tracer()
+	if input.Context == nil {
+		input.Context = context.Background()
+	}
+
 	// find the command handler
-	handler, err := c.Commander.FindHandler(input.CommandInput.Command, input.CommandInput.Chain...)
+	_, resolveSpan := tracer.Start(input.Context, SpanResolve)
+	resolveSpan.SetAttributes(inputAttributes(input)...)
+	handler, input, cmdTimeout, err := c.resolve(input)
+	endSpan(resolveSpan, err)
 	if err != nil {
 		return
 	}
-	
+
 	// run the command handler
-	err = handler(input)
+	var handlerSpan Span
+	input.Context, handlerSpan = tracer.Start(input.Context, SpanHandler)
+	handlerSpan.SetAttributes(inputAttributes(input)...)
+	// - with the timeout of the run, the command or the CLI, the first one set
+	switch {
+	case timeout > 0:
+	case cmdTimeout > 0:
+		timeout = cmdTimeout
+	default:
+		timeout = c.Timeout
+	}
+	logger.Debug("running handler", "timeout", timeout)
+	err = c.callHandlerTimeout(handler, input, timeout)
+	endSpan(handlerSpan, err)
 	if err != nil {
+		logger.Debug("handler failed", "error", err)
 		return err
 	}
 	
 	return
-}
\ No newline at end of file
+}
+
+// resolve is the method that finds the handler of the input, and returns the input with the flags resolved.
+// - if the commander supports it, the command is found with its specification:
+// deprecation warnings are written, the flags are validated and the timeout of the command is returned
+// - the flags of the input resolved have their defaults, the names of the flags given before the defaults are kept,
+// e.g. to skip the prompts they answer, and so is the specification of the flags, e.g. for the commands with raw args
+func (c CLI) resolve(input Input) (handler CommandHandler, resolved Input, timeout time.Duration, err error) {
+	logger := input.Logger
+	resolved = input
+	flags := input.Flags
+	resolved.given = flagNames(flags)
+
+	finder, ok := c.Commander.(CommandPathFinder)
+	if !ok {
+		handler, err = c.Commander.FindHandler(input.CommandInput.Command, input.CommandInput.Chain...)
+		if err != nil {
+			logger.Debug("command not found", "error", err)
+		}
+		return
+	}
+
+	p, err := finder.FindCommandPath(input.CommandInput.Command, input.CommandInput.Chain...)
+	if err != nil {
+		logger.Debug("command not found", "error", err)
+		return
+	}
+	logger.Debug("command resolved", "chain", strings.Join(p.Chain(), " "), "raw_args", p.Command.RawArgs)
+	timeout = p.Command.Timeout
+	specs := p.Flags()
+	resolved.specs = specs
+	err = c.checkDeprecated(p, input)
+	if err != nil {
+		return
+	}
+	// - commands with raw args parse their own args, the flags found by the parser are not checked
+	if p.Command.RawArgs {
+		handler = p.Command.Handler
+		return
+	}
+	flags, inline, err := readSecretFlags(specs, input.Flags, input.Reader, input.remote)
+	if err != nil {
+		return
+	}
+	// - a flag read from its file is given, e.g. --token-file answers the prompt of --token
+	resolved.given = flagNames(flags)
+	// - a client has no shell history and can not use --<name>-file, so it is not warned
+	for _, name := range inline {
+		if input.remote {
+			break
+		}
+		c.warn(input.ErrWriter, fmt.Sprintf("secret flag \"--%s\" given inline, it may be recorded in the shell history: use --%s-file instead", name, name))
+	}
+	command := strings.Join(append(p.Chain(), p.Command.Name), " ")
+	groupErr := validateFlagGroups(command, p.Command.FlagGroups, flags)
+	flags = applyFlagDefaults(specs, flags)
+	err = errors.Join(validateFlags(command, specs, flags), groupErr)
+	if err != nil {
+		logger.Debug("flags rejected", "error", err)
+		return
+	}
+	resolved.Flags = applyFlagValues(specs, flags)
+	handler = p.Command.Handler
+	return
+}
+
+// flagNames is the function that returns the set of the names of the flags.
+func flagNames(flags map[string]any) (names map[string]bool) {
+	names = make(map[string]bool, len(flags))
+	for name := range flags {
+		names[name] = true
+	}
+	return
+}
+
+// Help is the method that writes the help of a group or a command to the writer of the CLI.
+func (c CLI) Help(commandChain ...string) (err error) {
+	err = c.help(NewStyler(c.writer(), c.colorMode(os.Args[1:])), commandChain...)
+	return
+}
+
+// RenderError is the method that writes an error to the error writer of the CLI.
+// - it is styled in the same way as the rest of the output, honoring the --color flag
+// - an ExitError without error is not written, it was already reported
+func (c CLI) RenderError(err error) {
+	if err == nil {
+		return
+	}
+	// errors already reported, e.g. by an external plugin
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && exitErr.Err == nil {
+		return
+	}
+
+	w := c.errWriter()
+	s := NewStyler(w, c.colorMode(os.Args[1:]))
+	fmt.Fprintf(w, "%s %s\n", s.Bold(s.Color(ColorRed, "error:")), err)
+}
+
+// findRawArgsCommand is the method that finds the command with raw args at the start of the args.
+// - the longest chain of leading words that ends in such a command wins
+func (c CLI) findRawArgsCommand(args []string) (ci CommandInput, ok bool) {
+	finder, isFinder := c.Commander.(CommandPathFinder)
+	if !isFinder {
+		return
+	}
+
+	words := leadingWords(args)
+	for n := len(words); n > 0; n-- {
+		p, err := finder.FindCommandPath(words[n-1], words[:n-1]...)
+		if err != nil || !p.Command.RawArgs {
+			continue
+		}
+
+		ci = CommandInput{Chain: append([]string{}, words[:n-1]...), Command: words[n-1]}
+		ok = true
+		return
+	}
+	return
+}
+
+// checkDeprecated is the method that warns about the deprecated groups, command and flags used.
+// - in strict mode the first deprecated item is returned as an error instead
+func (c CLI) checkDeprecated(p CommandPath, input Input) (err error) {
+	var warnings []string
+	for i := 1; i < len(p.Managers); i++ {
+		if d := p.Managers[i].Deprecated; d != nil {
+			warnings = append(warnings, d.warning("group", p.Managers[i].Name))
+		}
+	}
+	if d := p.Command.Deprecated; d != nil {
+		warnings = append(warnings, d.warning("command", p.Command.Name))
+	}
+	for _, f := range p.Flags() {
+		if _, used := input.Flags[f.Name]; used && f.Deprecated != nil {
+			warnings = append(warnings, f.Deprecated.warning("flag", "--"+f.Name))
+		}
+	}
+	if len(warnings) == 0 {
+		return
+	}
+
+	// strict mode
+	if c.Strict {
+		err = fmt.Errorf("%w: %s", ErrDeprecated, warnings[0])
+		return
+	}
+
+	// warnings
+	c.warn(input.ErrWriter, warnings...)
+	return
+}
+
+// warn is the method that writes warnings to the error writer of the input, or of the CLI if nil.
+func (c CLI) warn(w io.Writer, warnings ...string) {
+	if w == nil {
+		w = c.errWriter()
+	}
+	s := NewStyler(w, c.Color)
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "%s %s\n", s.Bold(s.Color(ColorYellow, "warning:")), warning)
+	}
+}
+
+// complete is the method that writes the completion candidates of the args, one per line.
+func (c CLI) complete(args []string) (err error) {
+	completer, ok := c.Commander.(Completer)
+	if !ok {
+		return
+	}
+
+	w := c.writer()
+	for _, candidate := range completer.Complete(args...) {
+		_, err = fmt.Fprintln(w, candidate)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// help is the method that writes the help with the given styler.
+func (c CLI) help(s Styler, commandChain ...string) (err error) {
+	hw, ok := c.Commander.(HelpWriter)
+	if !ok {
+		err = ErrHelpNotSupported
+		return
+	}
+
+	err = hw.WriteHelp(c.writer(), s, commandChain...)
+	return
+}
+
+// colorMode is the method that returns the color mode, taking into account the --color flag of the args.
+// - it is used outside of a run, e.g. by RenderError with the args of the process
+// - an invalid value of the flag is ignored, as is a --color flag declared by the command
+func (c CLI) colorMode(args []string) (m ColorMode) {
+	m = c.Color
+	if _, declared := c.commandFlags(args).Find("color"); declared {
+		return
+	}
+	if value, ok, _ := extractFlag(args, "--color"); ok {
+		if mode, err := ParseColorMode(value); err == nil {
+			m = mode
+		}
+	}
+	return
+}
+
+// reader is the method that returns the reader of the CLI.
+// - it defaults to os.Stdin for a CLI not created with NewCLI
+func (c CLI) reader() (r io.Reader) {
+	r = c.Reader
+	if r == nil {
+		r = os.Stdin
+	}
+	return
+}
+
+// writer is the method that returns the writer of the CLI.
+// - it defaults to os.Stdout for a CLI not created with NewCLI
+func (c CLI) writer() (w io.Writer) {
+	w = c.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	return
+}
+
+// errWriter is the method that returns the error writer of the CLI.
+// - it defaults to os.Stderr for a CLI not created with NewCLI
+func (c CLI) errWriter() (w io.Writer) {
+	w = c.ErrWriter
+	if w == nil {
+		w = os.Stderr
+	}
+	return
+}
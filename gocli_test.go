@@ -1,11 +1,16 @@
 package gocli_test
 
 import (
+	"bytes"
 	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -155,4 +160,984 @@ func TestCLI_Run(t *testing.T) {
 		require.ErrorIs(t, err, errCmHandler)
 		require.EqualError(t, err, errCmHandler.Error())
 	})
-}
\ No newline at end of file
+}
+
+// TestCLI_Run_Color is the test for the color handling of the method Run.
+func TestCLI_Run_Color(t *testing.T) {
+	t.Run("success - case 01: --color=always is removed from the args and styles the input", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "cmd1", "--color=always"}
+		// - parser: mock
+		pr := gocli.NewParserMock()
+		pr.On("Parse", "cmd1").Return(gocli.Input{
+			CommandInput: gocli.CommandInput{Chain: []string{}, Command: "cmd1"},
+		}, nil)
+		// - commander: mock
+		var style gocli.Styler
+		cm := gocli.NewCommanderMock()
+		cm.On("FindHandler", "cmd1", mock.Anything).Return(
+			gocli.CommandHandler(func(i gocli.Input) (err error) {
+				style = i.Style
+				return
+			}),
+			nil,
+		)
+		// - cli
+		cli := gocli.NewCLI(pr, cm)
+		cli.Writer = &bytes.Buffer{}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, style.Enabled())
+		pr.AssertExpectations(t)
+		cm.AssertExpectations(t)
+	})
+
+	t.Run("success - case 02: NO_COLOR disables styling", func(t *testing.T) {
+		// arrange
+		// - env
+		t.Setenv("NO_COLOR", "1")
+		// - std-in
+		os.Args = []string{"app.exe", "cmd1"}
+		// - parser: mock
+		pr := gocli.NewParserMock()
+		pr.On("Parse", "cmd1").Return(gocli.Input{
+			CommandInput: gocli.CommandInput{Chain: []string{}, Command: "cmd1"},
+		}, nil)
+		// - commander: mock
+		var style gocli.Styler
+		cm := gocli.NewCommanderMock()
+		cm.On("FindHandler", "cmd1", mock.Anything).Return(
+			gocli.CommandHandler(func(i gocli.Input) (err error) {
+				style = i.Style
+				return
+			}),
+			nil,
+		)
+		// - cli
+		cli := gocli.NewCLI(pr, cm)
+		cli.Writer = os.Stdout
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.False(t, style.Enabled())
+	})
+
+	t.Run("success - case 03: --color declared by the command is left to it", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "show", "--color", "red", "-v", "2"}
+		// - commander: command with its own --color and -v flags
+		var flags map[string]any
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name: "show",
+			Flags: gocli.Flags{{Name: "color"}, {Name: "v"}},
+			Handler: func(i gocli.Input) (err error) {
+				flags = i.Flags
+				return
+			},
+		})
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Writer = &bytes.Buffer{}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"color": "red", "v": "2"}, flags)
+	})
+
+	t.Run("failure - case 01: invalid color mode", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "cmd1", "--color", "rainbow"}
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserMock(), gocli.NewCommanderMock())
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrInvalidColorMode)
+	})
+}
+
+// TestCLI_Run_Help is the test for the help handling of the method Run.
+func TestCLI_Run_Help(t *testing.T) {
+	t.Run("success - case 01: help of a group is written", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "db", "--help"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{Name: "migrate", Description: "runs the migrations"})
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserMock(), cm)
+		cli.Writer = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app db <command> [flags] [options]\n"+
+			"\ndatabase commands\n"+
+			"\nCommands:\n"+
+			"  migrate  runs the migrations\n", w.String())
+	})
+
+	t.Run("failure - case 01: commander does not support help", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "-h"}
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserMock(), gocli.NewCommanderMock())
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrHelpNotSupported)
+	})
+}
+
+// TestCLI_RenderError is the test for the method RenderError.
+func TestCLI_RenderError(t *testing.T) {
+	t.Run("success - case 01: error is written without style", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe"}
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(nil, nil)
+		cli.ErrWriter = w
+
+		// act
+		cli.RenderError(gocli.ErrCommandHandlerNotFound)
+
+		// assert
+		require.Equal(t, "error: command not found\n", w.String())
+	})
+
+	t.Run("success - case 02: error is written with style", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "--color=always"}
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(nil, nil)
+		cli.ErrWriter = w
+
+		// act
+		cli.RenderError(gocli.ErrCommandHandlerNotFound)
+
+		// assert
+		require.Equal(t, "\x1b[1m\x1b[31merror:\x1b[0m\x1b[0m command not found\n", w.String())
+	})
+}
+
+// TestCLI_Run_Output is the test for the output handling of the method Run.
+func TestCLI_Run_Output(t *testing.T) {
+	t.Run("success - case 01: --output json is removed from the args and given to the input", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "cmd1", "--output", "json"}
+		// - parser: mock
+		pr := gocli.NewParserMock()
+		pr.On("Parse", "cmd1").Return(gocli.Input{
+			CommandInput: gocli.CommandInput{Chain: []string{}, Command: "cmd1"},
+		}, nil)
+		// - commander: mock
+		var input gocli.Input
+		cm := gocli.NewCommanderMock()
+		cm.On("FindHandler", "cmd1", mock.Anything).Return(
+			gocli.CommandHandler(func(i gocli.Input) (err error) {
+				input = i
+				return
+			}),
+			nil,
+		)
+		// - cli
+		cli := gocli.NewCLI(pr, cm)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, gocli.OutputModeJSON, input.Output)
+		require.NotNil(t, input.Context)
+		pr.AssertExpectations(t)
+		cm.AssertExpectations(t)
+	})
+
+	t.Run("failure - case 01: invalid output mode", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "cmd1", "--output=yaml"}
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserMock(), gocli.NewCommanderMock())
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrInvalidOutputMode)
+	})
+}
+
+// TestCLI_Run_Deprecated is the test for the deprecation handling of the method Run.
+func TestCLI_Run_Deprecated(t *testing.T) {
+	// newCommander is the function that returns the commander used in the tests
+	newCommander := func(called *bool) (cm *gocli.CommanderManager) {
+		cm = gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.(gocli.GroupConfigurer).SetDeprecated(gocli.Deprecated{Message: "it will be removed in v2"})
+		db.AddCommand(gocli.Command{
+			Name: "migrate",
+			Flags: gocli.Flags{
+				{Name: "env", Default: "dev"},
+				{Name: "dry", Deprecated: &gocli.Deprecated{Replacement: "--plan"}},
+			},
+			Handler: func(i gocli.Input) (err error) {
+				*called = true
+				return
+			},
+		})
+		return
+	}
+
+	t.Run("success - case 01: warnings are written and the command is run", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "db", "migrate", "--dry", "true"}
+		// - cli
+		var called bool
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), newCommander(&called))
+		cli.ErrWriter = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, called)
+		require.Equal(t, "warning: group \"db\" is deprecated: it will be removed in v2\n"+
+			"warning: flag \"--dry\" is deprecated, use \"--plan\" instead\n", w.String())
+	})
+
+	t.Run("failure - case 01: strict mode", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "db", "migrate"}
+		// - cli
+		var called bool
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), newCommander(&called))
+		cli.ErrWriter = &bytes.Buffer{}
+		cli.Strict = true
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrDeprecated)
+		require.EqualError(t, err, "deprecated: group \"db\" is deprecated: it will be removed in v2")
+		require.False(t, called)
+	})
+}
+
+// TestCLI_Run_FlagDefaults is the test for the flag defaults of the method Run.
+func TestCLI_Run_FlagDefaults(t *testing.T) {
+	t.Run("success - case 01: default is set when the flag is not given", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "migrate"}
+		// - commander
+		var flags map[string]any
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name:  "migrate",
+			Flags: gocli.Flags{{Name: "env", Default: "dev"}},
+			Handler: func(i gocli.Input) (err error) {
+				flags = i.Flags
+				return
+			},
+		})
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"env": "dev"}, flags)
+	})
+}
+
+// TestCLI_Run_Complete is the test for the completion handling of the method Run.
+func TestCLI_Run_Complete(t *testing.T) {
+	t.Run("success - case 01: candidates are written one per line", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "__complete", "d"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{Name: "deploy"})
+		cm.AddCommand(gocli.Command{Name: "debug", Hidden: true})
+		cm.Group("db", "database commands")
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(nil, cm)
+		cli.Writer = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "deploy\ndb\n", w.String())
+	})
+}
+
+// TestCLI_Run_Bare is the test for a bare invocation of the method Run.
+func TestCLI_Run_Bare(t *testing.T) {
+	t.Run("success - case 01: help of the root is written", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{Name: "deploy", Description: "deploys the app"})
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserMock(), cm)
+		cli.Writer = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app <command> [flags] [options]\n"+
+			"\napp description\n"+
+			"\nCommands:\n"+
+			"  deploy  deploys the app\n", w.String())
+	})
+}
+
+// TestCLI_Run_FlagValidation is the test for the flag validation of the method Run.
+func TestCLI_Run_FlagValidation(t *testing.T) {
+	// newCommander is the function that returns the commander used in the tests
+	newCommander := func() (cm *gocli.CommanderManager) {
+		cm = gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name: "migrate",
+			Flags: gocli.Flags{
+				{Name: "env", Required: true},
+				{Name: "steps", Type: gocli.FlagTypeInt},
+			},
+			Handler: func(i gocli.Input) (err error) { return },
+		})
+		return
+	}
+
+	t.Run("success - case 01: flags are valid", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "migrate", "--env", "prod", "--steps", "3"}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), newCommander())
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("failure - case 01: required flag is missing", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "migrate", "--steps", "3"}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), newCommander())
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrFlagRequired)
+		require.EqualError(t, err, "migrate: flag is required: --env")
+	})
+
+	t.Run("failure - case 02: flag has an invalid type", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "migrate", "--env", "prod", "--steps", "three"}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), newCommander())
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrFlagInvalidType)
+		require.EqualError(t, err, `migrate: flag has an invalid type: --steps: expected int, got "three"`)
+	})
+}
+
+// TestCLI_Run_Logging is the test for the logging flags of the method Run.
+func TestCLI_Run_Logging(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests, writing the logs to the buffer
+	newCLI := func(logs *bytes.Buffer) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{
+			Name: "migrate",
+			Handler: func(i gocli.Input) (err error) {
+				i.Logger.Info("migrating")
+				i.Logger.Error("migration failed")
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = logs
+		return
+	}
+
+	t.Run("success - case 01: default level is warn", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		var logs bytes.Buffer
+		cli := newCLI(&logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.NotContains(t, logs.String(), "migrating")
+		require.Contains(t, logs.String(), `level=ERROR msg="migration failed" run_id=`)
+		require.Contains(t, logs.String(), `command="db migrate"`)
+	})
+
+	t.Run("success - case 02: -v enables info logs", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "-v"}
+		var logs bytes.Buffer
+		cli := newCLI(&logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), "msg=migrating")
+		require.NotContains(t, logs.String(), "level=DEBUG")
+	})
+
+	t.Run("success - case 03: -vv enables the logs of the framework", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "-vv", "db", "migrate"}
+		var logs bytes.Buffer
+		cli := newCLI(&logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), `msg="parsing args"`)
+		require.Contains(t, logs.String(), `msg="command resolved"`)
+	})
+
+	t.Run("success - case 04: --quiet and json format", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--quiet", "--log-format=json"}
+		var logs bytes.Buffer
+		cli := newCLI(&logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.NotContains(t, logs.String(), "migrating")
+		require.Contains(t, logs.String(), `"msg":"migration failed"`)
+		require.Contains(t, logs.String(), `"command":"db migrate"`)
+	})
+
+	t.Run("success - case 05: --log-level wins", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--quiet", "--log-level", "info"}
+		var logs bytes.Buffer
+		cli := newCLI(&logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), "msg=migrating")
+	})
+
+	t.Run("failure - case 01: invalid log level", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--log-level", "loud"}
+		cli := newCLI(&bytes.Buffer{})
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidLogLevel)
+	})
+
+	t.Run("failure - case 02: invalid log format", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--log-format", "xml"}
+		cli := newCLI(&bytes.Buffer{})
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidLogFormat)
+	})
+}
+
+// TestCLI_Run_FlagRules is the test for the validation rules of the flags of the method Run.
+func TestCLI_Run_FlagRules(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func(dir string) (cli gocli.CLI) {
+		min, max, maxBytes := 1.0, 10.0, float64(10<<20)
+		cm := gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{
+			Name: "migrate",
+			Flags: gocli.Flags{
+				{Name: "env", Enum: []string{"dev", "prod"}},
+				{Name: "steps", Type: gocli.FlagTypeInt, Min: &min, Max: &max},
+				{Name: "name", Pattern: `^[a-z_]+$`},
+				{Name: "dir", Exists: gocli.FlagExistsDir},
+				{Name: "timeout", Type: gocli.FlagTypeDuration, Validate: func(value any) (err error) {
+					if value.(time.Duration) > time.Hour {
+						err = errors.New("must be at most 1h")
+					}
+					return
+				}},
+				{Name: "limit", Value: gocli.NewByteSize, Max: &maxBytes},
+				{Name: "wait", Type: gocli.FlagTypeDuration, Min: &min},
+			},
+			Handler: func(i gocli.Input) (err error) { return },
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		return
+	}
+
+	t.Run("success - case 01: values pass the rules", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		os.Args = []string{"app.exe", "db", "migrate", "--env", "prod", "--steps", "10", "--name", "add_users", "--dir", dir, "--timeout", "30m"}
+		cli := newCLI(dir)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("success - case 02: minimum and maximum of byte sizes and durations", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		os.Args = []string{"app.exe", "db", "migrate", "--limit", "10MiB", "--wait", "5s"}
+		cli := newCLI(dir)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("failure - case 01: all the failures are returned", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		os.Args = []string{"app.exe", "db", "migrate", "--env", "qa", "--steps", "11", "--name", "AddUsers", "--dir", dir + "/missing", "--timeout", "2h"}
+		cli := newCLI(dir)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagInvalidValue)
+		require.EqualError(t, err, strings.Join([]string{
+			`db migrate: flag has an invalid value: --env: enum: "qa" is not one of dev, prod`,
+			`db migrate: flag has an invalid value: --steps: max: "11" is greater than 10`,
+			`db migrate: flag has an invalid value: --name: pattern: "AddUsers" does not match ^[a-z_]+$`,
+			`db migrate: flag has an invalid value: --dir: exists: "` + dir + `/missing" does not exist`,
+			`db migrate: flag has an invalid value: --timeout: validate: must be at most 1h`,
+		}, "\n"))
+
+		var flagErr *gocli.FlagError
+		require.ErrorAs(t, err, &flagErr)
+		require.Equal(t, "db migrate", flagErr.Command)
+		require.Equal(t, "env", flagErr.Flag)
+		require.Equal(t, gocli.FlagRuleEnum, flagErr.Rule)
+	})
+
+	t.Run("failure - case 02: minimum and path kind", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		file := filepath.Join(dir, "file.txt")
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+		os.Args = []string{"app.exe", "db", "migrate", "--steps", "0", "--dir", file}
+		cli := newCLI(dir)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.EqualError(t, err, strings.Join([]string{
+			`db migrate: flag has an invalid value: --steps: min: "0" is less than 1`,
+			`db migrate: flag has an invalid value: --dir: exists: "` + file + `" is not a dir`,
+		}, "\n"))
+	})
+
+	t.Run("failure - case 03: byte size and duration out of range", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		os.Args = []string{"app.exe", "db", "migrate", "--limit", "11MiB", "--wait", "500ms"}
+		cli := newCLI(dir)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.EqualError(t, err, strings.Join([]string{
+			`db migrate: flag has an invalid value: --limit: max: "11MiB" is greater than 10485760`,
+			`db migrate: flag has an invalid value: --wait: min: "500ms" is less than 1`,
+		}, "\n"))
+	})
+}
+
+// TestCLI_Run_FlagGroups is the test for the flag groups of the method Run.
+func TestCLI_Run_FlagGroups(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func() (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name: "import",
+			Flags: gocli.Flags{
+				{Name: "file"},
+				{Name: "stdin", Type: gocli.FlagTypeBool},
+				{Name: "user"},
+				{Name: "password", Default: "none"},
+				{Name: "format"},
+				{Name: "schema"},
+			},
+			FlagGroups: []gocli.FlagGroup{
+				{Kind: gocli.FlagGroupExactlyOne, Flags: []string{"file", "stdin"}},
+				{Kind: gocli.FlagGroupTogether, Flags: []string{"user", "password"}},
+				{Kind: gocli.FlagGroupAtLeastOne, Flags: []string{"format", "schema"}},
+				{Kind: gocli.FlagGroupExclusive, Flags: []string{"format", "schema"}},
+			},
+			Handler: func(i gocli.Input) (err error) { return },
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		return
+	}
+
+	t.Run("success - case 01: flags satisfy the groups", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "import", "--file", "users.csv", "--user", "admin", "--password", "pass", "--format", "csv"}
+		cli := newCLI()
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("failure - case 01: all the groups violated are returned", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "import", "--file", "users.csv", "--stdin", "true", "--user", "admin", "--format", "csv", "--schema", "users"}
+		cli := newCLI()
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagsExclusive)
+		require.ErrorIs(t, err, gocli.ErrFlagsRequiredTogether)
+		require.EqualError(t, err, strings.Join([]string{
+			"import: flags are mutually exclusive: --file, --stdin",
+			"import: flags are required together: --user, --password (missing --password)",
+			"import: flags are mutually exclusive: --format, --schema",
+		}, "\n"))
+	})
+
+	t.Run("failure - case 02: none of the required flags is given", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "import", "--user", "admin"}
+		cli := newCLI()
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagsOneRequired)
+		var flagErr *gocli.FlagError
+		require.ErrorAs(t, err, &flagErr)
+		require.Equal(t, "file,stdin", flagErr.Flag)
+		require.Equal(t, gocli.FlagRule(gocli.FlagGroupExactlyOne), flagErr.Rule)
+		require.EqualError(t, err, strings.Join([]string{
+			"import: one of the flags is required: --file, --stdin",
+			"import: flags are required together: --user, --password (missing --password)",
+			"import: one of the flags is required: --format, --schema",
+		}, "\n"))
+	})
+}
+
+// TestCLI_Run_PersistentFlags tests the persistent flags of the groups.
+func TestCLI_Run_PersistentFlags(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests, with the flags given to the handler
+	newCLI := func(flags *map[string]any) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddFlag(gocli.Flag{Name: "profile", Default: "default"})
+		db := cm.Group("db", "database commands")
+		db.(gocli.GroupConfigurer).AddFlag(gocli.Flag{Name: "region", Enum: []string{"eu", "us"}, Required: true})
+		db.AddCommand(gocli.Command{
+			Name: "migrate",
+			Flags: gocli.Flags{{Name: "env", Default: "dev"}},
+			Handler: func(i gocli.Input) (err error) {
+				*flags = i.Flags
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		return
+	}
+
+	t.Run("success - case 01: persistent flags given within the chain", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "--profile", "prod", "db", "--region", "eu", "migrate"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"profile": "prod", "region": "eu", "env": "dev"}, flags)
+	})
+
+	t.Run("success - case 02: persistent flags given after the command, with defaults", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--region", "us", "--env", "prod"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"profile": "default", "region": "us", "env": "prod"}, flags)
+	})
+
+	t.Run("failure - case 01: persistent flags are validated", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagRequired)
+		require.EqualError(t, err, "db migrate: flag is required: --region")
+		require.Nil(t, flags)
+	})
+
+	t.Run("failure - case 02: flags of the command given within the chain", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "--env", "prod", "migrate", "--region", "eu"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidArgs)
+		require.Nil(t, flags)
+	})
+
+	t.Run("failure - case 03: words after the flags are not moved into the chain", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--env", "prod", "extra"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidArgs)
+		require.Nil(t, flags)
+	})
+}
+
+// TestCLI_Run_FlagValues tests the flags with a custom type.
+func TestCLI_Run_FlagValues(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests, with the flags given to the handler
+	newCLI := func(flags *map[string]any) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name: "upload",
+			Flags: gocli.Flags{
+				{Name: "limit", Value: gocli.NewByteSize, Default: "1MiB"},
+				{Name: "labels", Value: gocli.NewKeyValues},
+			},
+			Handler: func(i gocli.Input) (err error) {
+				*flags = i.Flags
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		return
+	}
+
+	t.Run("success - case 01: the handler is given the parsed values", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "upload", "--labels", "team=core,env=prod"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		limit := gocli.ByteSize(1 << 20)
+		require.Equal(t, map[string]any{
+			"limit":  &limit,
+			"labels": &gocli.KeyValues{"team": "core", "env": "prod"},
+		}, flags)
+	})
+
+	t.Run("failure - case 01: the value does not match the type", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "upload", "--limit", "lots"}
+		var flags map[string]any
+		cli := newCLI(&flags)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagInvalidType)
+		require.EqualError(t, err, "upload: flag has an invalid type: --limit: expected bytes, got \"lots\"")
+		require.Nil(t, flags)
+	})
+}
+
+// TestCLI_Run_Expansion tests the expansion of response files and environment variables in the args.
+func TestCLI_Run_Expansion(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests, with the flags given to the handler
+	newCLI := func(flags *map[string]any, logs *bytes.Buffer) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name:  "delete",
+			Flags: gocli.Flags{{Name: "ids"}, {Name: "token", Secret: true}},
+			Handler: func(i gocli.Input) (err error) {
+				*flags = i.Flags
+				return
+			},
+		})
+		cm.AddCommand(gocli.Command{
+			Name:    "exec",
+			RawArgs: true,
+			Handler: func(i gocli.Input) (err error) {
+				*flags = map[string]any{"args": i.Args}
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = logs
+		cli.ResponseFiles = true
+		cli.ExpandEnv = true
+		return
+	}
+
+	t.Run("success - case 01: response file with interpolated values", func(t *testing.T) {
+		// arrange
+		t.Setenv("GOCLI_TEST_TOKEN", "s3cr3t")
+		path := filepath.Join(t.TempDir(), "args.txt")
+		require.NoError(t, os.WriteFile(path, []byte("--ids 1,2,3\n--token ${GOCLI_TEST_TOKEN}\n"), 0o644))
+		os.Args = []string{"app.exe", "delete", "@" + path, "-vv"}
+		var flags map[string]any
+		var logs bytes.Buffer
+		cli := newCLI(&flags, &logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"ids": "1,2,3", "token": "s3cr3t"}, flags)
+		require.Contains(t, logs.String(), `msg="args expanded"`)
+		require.Contains(t, logs.String(), `argv="[delete --ids 1,2,3 --token *** -vv]"`)
+		require.NotContains(t, logs.String(), "s3cr3t")
+	})
+
+	t.Run("success - case 02: quoted values of a response file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "args.txt")
+		require.NoError(t, os.WriteFile(path, []byte(`--ids "1,2,3" 'ls -la'`+"\n"), 0o644))
+		var deleteFlags, execFlags map[string]any
+
+		// act
+		os.Args = []string{"app.exe", "delete", "@" + path}
+		errDelete := newCLI(&deleteFlags, &bytes.Buffer{}).Run()
+		os.Args = []string{"app.exe", "exec", "@" + path}
+		errExec := newCLI(&execFlags, &bytes.Buffer{}).Run()
+
+		// assert
+		require.ErrorIs(t, errDelete, gocli.ErrExpandedArgSpace)
+		require.EqualError(t, errDelete, `expanded arg has white space: "ls -la", only commands with raw args accept it`)
+		require.Nil(t, deleteFlags)
+		require.NoError(t, errExec)
+		require.Equal(t, map[string]any{"args": []string{"exec", "--ids", "1,2,3", "ls -la"}}, execFlags)
+	})
+
+	t.Run("failure - case 01: undefined variable", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "delete", "--ids", "${GOCLI_TEST_UNDEFINED}"}
+		var flags map[string]any
+		var logs bytes.Buffer
+		cli := newCLI(&flags, &logs)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrEnvUndefined)
+		require.Nil(t, flags)
+	})
+}
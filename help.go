@@ -0,0 +1,192 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrHelpNotSupported is the error that returns when the commander can not write help.
+	ErrHelpNotSupported = errors.New("help not supported by commander")
+)
+
+// HelpWriter is the interface that wraps the method to write the help of a command chain.
+// It is optional for a Commander, the CLI only renders help if the Commander implements it.
+type HelpWriter interface {
+	// WriteHelp is the method that writes the help of a group or a command.
+	// - the last element of the chain can be either a group or a command
+	WriteHelp(w io.Writer, s Styler, commandChain ...string) (err error)
+}
+
+// WriteHelp is the method that writes the help of a group or a command of the command manager.
+func (c *CommanderManager) WriteHelp(w io.Writer, s Styler, commandChain ...string) (err error) {
+	// group help
+	cmg, err := c.FindCommandManager(commandChain...)
+	if err == nil {
+		err = c.writeGroupHelp(w, s, cmg, commandChain)
+		return
+	}
+
+	// command help
+	size := len(commandChain)
+	if size == 0 {
+		return
+	}
+	cmg, err = c.FindCommandManager(commandChain[:size-1]...)
+	if err != nil {
+		return
+	}
+	cmd, err := cmg.Cmds.FindCommand(commandChain[size-1])
+	if err != nil {
+		return
+	}
+	err = c.writeCommandHelp(w, s, cmd, commandChain[:size-1])
+	return
+}
+
+// writeGroupHelp is the method that writes the help of a group.
+func (c *CommanderManager) writeGroupHelp(w io.Writer, s Styler, cmg *CommanderManager, chain []string) (err error) {
+	var b strings.Builder
+
+	// usage
+	fmt.Fprintf(&b, "%s %s <command> [flags] [options]\n", s.Bold("Usage:"), c.usagePath(chain))
+	// description
+	if cmg.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", cmg.Description)
+	}
+
+	// commands
+	rows := make([][2]string, 0, len(cmg.Cmds))
+	for _, cmd := range cmg.Cmds {
+		if cmd.Hidden {
+			continue
+		}
+		rows = append(rows, [2]string{cmd.Name, helpDescription(s, cmd.Description, cmd.Deprecated)})
+	}
+	writeHelpSection(&b, s, "Commands:", rows)
+
+	// groups
+	rows = make([][2]string, 0, len(cmg.CommandManagers))
+	for _, sub := range cmg.CommandManagers {
+		if sub.Hidden {
+			continue
+		}
+		rows = append(rows, [2]string{sub.Name, helpDescription(s, sub.Description, sub.Deprecated)})
+	}
+	writeHelpSection(&b, s, "Groups:", rows)
+
+	// persistent flags of the group and its parents
+	managers, _ := c.findCommandManagers(chain...)
+	writeHelpSection(&b, s, "Flags:", helpFlagRows(s, inheritFlags(nil, managers)))
+
+	// user aliases of the root
+	if len(chain) == 0 && c.Aliases != nil {
+		aliases, _ := c.Aliases.List()
+		writeHelpSection(&b, s, "User Aliases:", c.aliasRows(s, aliases))
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return
+}
+
+// writeCommandHelp is the method that writes the help of a command.
+func (c *CommanderManager) writeCommandHelp(w io.Writer, s Styler, cmd Command, chain []string) (err error) {
+	var b strings.Builder
+
+	// usage
+	fmt.Fprintf(&b, "%s %s %s [flags] [options]\n", s.Bold("Usage:"), c.usagePath(chain), cmd.Name)
+	// description
+	if cmd.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", cmd.Description)
+	}
+	if cmd.Deprecated != nil {
+		fmt.Fprintf(&b, "\n%s\n", s.Color(ColorYellow, cmd.Deprecated.warning("command", cmd.Name)))
+	}
+	// aliases
+	if len(cmd.Aliases) > 0 {
+		fmt.Fprintf(&b, "\n%s %s\n", s.Bold("Aliases:"), strings.Join(cmd.Aliases, ", "))
+	}
+
+	// flags
+	writeHelpSection(&b, s, "Flags:", helpFlagRows(s, cmd.Flags))
+	// - persistent flags of the groups, not shadowed by the flags of the command
+	managers, _ := c.findCommandManagers(chain...)
+	inherited := inheritFlags(cmd.Flags, managers)[len(cmd.Flags):]
+	writeHelpSection(&b, s, "Inherited Flags:", helpFlagRows(s, inherited))
+
+	// flag groups
+	rows := make([][2]string, 0, len(cmd.FlagGroups))
+	for _, g := range cmd.FlagGroups {
+		rows = append(rows, [2]string{g.Names(), g.Description()})
+	}
+	writeHelpSection(&b, s, "Flag Groups:", rows)
+
+	// examples
+	if len(cmd.Examples) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", s.Bold("Examples:"))
+		for _, example := range cmd.Examples {
+			fmt.Fprintf(&b, "  %s\n", example)
+		}
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return
+}
+
+// usagePath is the method that returns the full path of a chain, starting with the root name.
+func (c *CommanderManager) usagePath(chain []string) (p string) {
+	p = strings.Join(append([]string{c.Name}, chain...), " ")
+	p = strings.TrimSpace(p)
+	return
+}
+
+// helpDescription is the function that returns the description of an item, marking it if deprecated.
+func helpDescription(s Styler, description string, d *Deprecated) (r string) {
+	r = description
+	if d != nil {
+		r = strings.TrimSpace(r + " " + s.Dim("(deprecated)"))
+	}
+	return
+}
+
+// helpFlagRows is the function that returns the help rows of the non-hidden flags.
+func helpFlagRows(s Styler, flags Flags) (rows [][2]string) {
+	rows = make([][2]string, 0, len(flags))
+	for _, f := range flags {
+		if f.Hidden {
+			continue
+		}
+		rows = append(rows, [2]string{"--" + f.Name, helpFlagDescription(s, f)})
+	}
+	return
+}
+
+// helpFlagDescription is the function that returns the description of a flag with its default.
+func helpFlagDescription(s Styler, f Flag) (r string) {
+	r = f.Describe(s.Dim, true)
+	r = helpDescription(s, r, f.Deprecated)
+	return
+}
+
+// writeHelpSection is the function that writes a titled section of aligned name-description rows.
+// - empty sections are not written
+func writeHelpSection(b *strings.Builder, s Styler, title string, rows [][2]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	// align by the longest name
+	var width int
+	for _, row := range rows {
+		width = max(width, len(row[0]))
+	}
+
+	fmt.Fprintf(b, "\n%s\n", s.Bold(title))
+	for _, row := range rows {
+		// padding is computed on the raw name, so escape sequences do not break the alignment
+		padding := strings.Repeat(" ", width-len(row[0]))
+		fmt.Fprintf(b, "  %s%s  %s\n", s.Color(ColorCyan, row[0]), padding, row[1])
+	}
+}
@@ -0,0 +1,168 @@
+package gocli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommanderManager_WriteHelp is the test for the method WriteHelp.
+func TestCommanderManager_WriteHelp(t *testing.T) {
+	// cmg is the command manager used in the tests
+	cmg := &gocli.CommanderManager{
+		Name:        "app",
+		Description: "app description",
+		Cmds: gocli.Commands{
+			{Name: "version", Description: "prints the version"},
+			{Name: "debug", Description: "debugs the app", Hidden: true},
+		},
+		CommandManagers: []*gocli.CommanderManager{
+			{
+				Name:        "db",
+				Description: "database commands",
+				Cmds: gocli.Commands{
+					{Name: "migrate", Description: "runs the migrations", Flags: gocli.Flags{
+						{Name: "env", Description: "environment", Default: "dev", Enum: []string{"dev", "prod"}},
+						{Name: "dry", Description: "dry run", Deprecated: &gocli.Deprecated{}},
+						{Name: "legacy", Hidden: true},
+					}, FlagGroups: []gocli.FlagGroup{
+						{Kind: gocli.FlagGroupExclusive, Flags: []string{"env", "dry"}},
+					}},
+					{Name: "seed", Description: "seeds the database", Deprecated: &gocli.Deprecated{Replacement: "db fixtures"}},
+				},
+			},
+			{Name: "internal", Hidden: true},
+		},
+	}
+
+	t.Run("success - case 01: help of the root", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		err := cmg.WriteHelp(w, gocli.Styler{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app <command> [flags] [options]\n"+
+			"\napp description\n"+
+			"\nCommands:\n"+
+			"  version  prints the version\n"+
+			"\nGroups:\n"+
+			"  db  database commands\n", w.String())
+	})
+
+	t.Run("success - case 02: help of a group", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		err := cmg.WriteHelp(w, gocli.Styler{}, "db")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app db <command> [flags] [options]\n"+
+			"\ndatabase commands\n"+
+			"\nCommands:\n"+
+			"  migrate  runs the migrations\n"+
+			"  seed     seeds the database (deprecated)\n", w.String())
+	})
+
+	t.Run("success - case 03: help of a command", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		err := cmg.WriteHelp(w, gocli.Styler{}, "db", "migrate")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app db migrate [flags] [options]\n"+
+			"\nruns the migrations\n"+
+			"\nFlags:\n"+
+			"  --env  environment (one of: dev, prod) (default: dev)\n"+
+			"  --dry  dry run (deprecated)\n"+
+			"\nFlag Groups:\n"+
+			"  --env, --dry  mutually exclusive\n", w.String())
+	})
+
+	t.Run("success - case 04: help of a deprecated command", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		err := cmg.WriteHelp(w, gocli.Styler{}, "db", "seed")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Usage: app db seed [flags] [options]\n"+
+			"\nseeds the database\n"+
+			"\ncommand \"seed\" is deprecated, use \"db fixtures\" instead\n", w.String())
+	})
+
+	t.Run("success - case 05: help is styled", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		s := gocli.NewStyler(w, gocli.ColorModeAlways)
+
+		// act
+		err := cmg.WriteHelp(w, s, "db", "migrate")
+
+		// assert
+		require.NoError(t, err)
+		require.Contains(t, w.String(), s.Bold("Usage:"))
+	})
+
+	t.Run("success - case 06: persistent flags of the groups", func(t *testing.T) {
+		// arrange
+		cmg := &gocli.CommanderManager{
+			Name:  "app",
+			Flags: gocli.Flags{{Name: "profile", Description: "config profile"}},
+			CommandManagers: []*gocli.CommanderManager{
+				{
+					Name:  "db",
+					Flags: gocli.Flags{{Name: "region", Description: "database region"}},
+					Cmds: gocli.Commands{
+						{Name: "migrate", Flags: gocli.Flags{{Name: "env", Description: "environment"}}},
+					},
+				},
+			},
+		}
+		wg := &bytes.Buffer{}
+		wc := &bytes.Buffer{}
+
+		// act
+		errGroup := cmg.WriteHelp(wg, gocli.Styler{}, "db")
+		errCommand := cmg.WriteHelp(wc, gocli.Styler{}, "db", "migrate")
+
+		// assert
+		require.NoError(t, errGroup)
+		require.Equal(t, "Usage: app db <command> [flags] [options]\n"+
+			"\nCommands:\n"+
+			"  migrate  \n"+
+			"\nFlags:\n"+
+			"  --region   database region\n"+
+			"  --profile  config profile\n", wg.String())
+		require.NoError(t, errCommand)
+		require.Equal(t, "Usage: app db migrate [flags] [options]\n"+
+			"\nFlags:\n"+
+			"  --env  environment\n"+
+			"\nInherited Flags:\n"+
+			"  --region   database region\n"+
+			"  --profile  config profile\n", wc.String())
+	})
+
+	t.Run("failure - case 01: command not found", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		err := cmg.WriteHelp(w, gocli.Styler{}, "db", "drop")
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrCommandHandlerNotFound)
+	})
+}
@@ -0,0 +1,91 @@
+package gocli
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+var (
+	// ErrInvalidLogLevel is the error that returns when the log level is invalid.
+	ErrInvalidLogLevel = errors.New("invalid log level")
+	// ErrInvalidLogFormat is the error that returns when the log format is invalid.
+	ErrInvalidLogFormat = errors.New("invalid log format")
+)
+
+// LogFormat is the type that represents the format of the logs.
+type LogFormat string
+
+const (
+	// LogFormatText is the key=value format of slog.TextHandler.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON is the format of slog.JSONHandler.
+	LogFormatJSON LogFormat = "json"
+)
+
+// ParseLogFormat is the function that parses a log format, as given to the --log-format flag.
+func ParseLogFormat(value string) (f LogFormat, err error) {
+	switch LogFormat(value) {
+	case LogFormatText, LogFormatJSON:
+		f = LogFormat(value)
+	case "":
+		f = LogFormatText
+	default:
+		err = fmt.Errorf("%w: %q (expected text or json)", ErrInvalidLogFormat, value)
+	}
+	return
+}
+
+// ParseLogLevel is the function that parses a log level, as given to the --log-level flag.
+// - it accepts the names of slog, e.g. debug, info, warn, error, with an optional offset such as warn+2
+func ParseLogLevel(value string) (l slog.Level, err error) {
+	if e := l.UnmarshalText([]byte(value)); e != nil {
+		err = fmt.Errorf("%w: %q (expected debug, info, warn or error)", ErrInvalidLogLevel, value)
+	}
+	return
+}
+
+// newLogger is the function that returns a new logger writing to w, with a run id attribute.
+func newLogger(w io.Writer, level slog.Level, format LogFormat) (l *slog.Logger) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	switch format {
+	case LogFormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		h = slog.NewTextHandler(w, opts)
+	}
+
+	l = slog.New(h).With("run_id", newRunID())
+	return
+}
+
+// newRunID is the function that returns a random id, shared by the logs of a run.
+func newRunID() (id string) {
+	id = randomHex(8)
+	return
+}
+
+// randomHex is the function that returns n random bytes encoded in hex.
+func randomHex(n int) (r string) {
+	b := make([]byte, n)
+	rand.Read(b)
+	r = hex.EncodeToString(b)
+	return
+}
+
+// commandAttr is the function that returns the command chain of an input as a log attribute.
+func commandAttr(ci CommandInput) (attr slog.Attr) {
+	path := append([]string{}, ci.Chain...)
+	if ci.Command != "" {
+		path = append(path, ci.Command)
+	}
+
+	attr = slog.String("command", strings.Join(path, " "))
+	return
+}
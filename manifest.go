@@ -0,0 +1,317 @@
+package gocli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrManifestInvalidFormat is the error that returns when the format of the manifest is not supported.
+	ErrManifestInvalidFormat = errors.New("invalid manifest format")
+)
+
+// ManifestVersion is the version of the format of the manifest.
+// - it changes only when the format changes in a way that breaks its readers
+const ManifestVersion = "1"
+
+// Manifest is the struct that represents the full command tree as a machine-readable document.
+type Manifest struct {
+	// Version is the version of the format of the manifest.
+	Version string `json:"version"`
+	// Root is the root group, named after the app.
+	Root ManifestGroup `json:"root"`
+}
+
+// ManifestGroup is the struct that represents a group of the manifest.
+type ManifestGroup struct {
+	// Name is the name of the group.
+	Name string `json:"name"`
+	// Path is the chain of the group, without the root.
+	Path []string `json:"path"`
+	// Description is the description of the group.
+	Description string `json:"description,omitempty"`
+	// Default is the command run when the group itself is run.
+	Default string `json:"default,omitempty"`
+	// Hidden is the flag that indicates if the group is hidden.
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated is the deprecation of the group.
+	Deprecated *Deprecated `json:"deprecated,omitempty"`
+	// Commands are the commands of the group.
+	Commands []ManifestCommand `json:"commands"`
+	// Groups are the groups of the group.
+	Groups []ManifestGroup `json:"groups"`
+	// Flags are the persistent flags of the group, accepted by every command under it.
+	Flags []ManifestFlag `json:"flags,omitempty"`
+}
+
+// ManifestCommand is the struct that represents a command of the manifest.
+type ManifestCommand struct {
+	// Name is the name of the command.
+	Name string `json:"name"`
+	// Path is the chain of the command followed by its name, without the root.
+	Path []string `json:"path"`
+	// Description is the description of the command.
+	Description string `json:"description,omitempty"`
+	// Aliases are the aliases of the command.
+	Aliases []string `json:"aliases,omitempty"`
+	// Examples are the examples of the command.
+	Examples []string `json:"examples,omitempty"`
+	// RawArgs is the flag that indicates if the command accepts any args.
+	RawArgs bool `json:"raw_args,omitempty"`
+	// Timeout is the timeout of the command, e.g. "5m0s", empty if it has none.
+	Timeout string `json:"timeout,omitempty"`
+	// Hidden is the flag that indicates if the command is hidden.
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated is the deprecation of the command.
+	Deprecated *Deprecated `json:"deprecated,omitempty"`
+	// Flags are the flags of the command.
+	Flags []ManifestFlag `json:"flags"`
+	// FlagGroups are the relationships between the flags of the command.
+	FlagGroups []FlagGroup `json:"flag_groups,omitempty"`
+	// InputSchema is the JSON Schema of the input of the command, an object with a property per flag.
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// ManifestFlag is the struct that represents a flag of the manifest.
+type ManifestFlag struct {
+	// Name is the name of the flag, without dashes.
+	Name string `json:"name"`
+	// Description is the description of the flag.
+	Description string `json:"description,omitempty"`
+	// Type is the type of the value of the flag.
+	Type FlagType `json:"type"`
+	// Default is the value of the flag when it is not given.
+	Default string `json:"default,omitempty"`
+	// Required is the flag that indicates if the flag must be given.
+	Required bool `json:"required,omitempty"`
+	// Hidden is the flag that indicates if the flag is hidden.
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated is the deprecation of the flag.
+	Deprecated *Deprecated `json:"deprecated,omitempty"`
+	// Secret is the flag that indicates if the value of the flag is a secret, its default is redacted.
+	Secret bool `json:"secret,omitempty"`
+	// Enum are the allowed values of the flag.
+	Enum []string `json:"enum,omitempty"`
+	// Min is the minimum of the value of a numeric flag.
+	Min *float64 `json:"min,omitempty"`
+	// Max is the maximum of the value of a numeric flag.
+	Max *float64 `json:"max,omitempty"`
+	// Pattern is the regexp pattern the value of the flag must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Exists is the kind of path the value of the flag must exist as.
+	Exists FlagExists `json:"exists,omitempty"`
+}
+
+// NewManifest is the function that returns the manifest of a command manager tree.
+// - hidden and deprecated items are included, with their state
+func NewManifest(cm *CommanderManager) (m Manifest) {
+	m = Manifest{
+		Version: ManifestVersion,
+		Root: newManifestGroup(cm, []string{}, nil),
+	}
+	return
+}
+
+// newManifestGroup is the function that returns the manifest of a group and its descendants.
+// - managers are the parents of the group, whose persistent flags are inherited by its commands
+func newManifestGroup(cm *CommanderManager, path []string, managers []*CommanderManager) (g ManifestGroup) {
+	managers = append(append([]*CommanderManager{}, managers...), cm)
+
+	g = ManifestGroup{
+		Name: cm.Name,
+		Path: path,
+		Description: cm.Description,
+		Default: cm.Default,
+		Hidden: cm.Hidden,
+		Deprecated: cm.Deprecated,
+		Commands: []ManifestCommand{},
+		Groups: []ManifestGroup{},
+	}
+	if len(cm.Flags) > 0 {
+		g.Flags = newManifestFlags(cm.Flags)
+	}
+	for _, cmd := range cm.Cmds {
+		g.Commands = append(g.Commands, newManifestCommand(cmd, append(append([]string{}, path...), cmd.Name), managers))
+	}
+	for _, sub := range cm.CommandManagers {
+		g.Groups = append(g.Groups, newManifestGroup(sub, append(append([]string{}, path...), sub.Name), managers))
+	}
+	return
+}
+
+// newManifestCommand is the function that returns the manifest of a command.
+// - the input schema includes the persistent flags of the managers of the command
+func newManifestCommand(cmd Command, path []string, managers []*CommanderManager) (c ManifestCommand) {
+	c = ManifestCommand{
+		Name: cmd.Name,
+		Path: path,
+		Description: cmd.Description,
+		Aliases: cmd.Aliases,
+		Examples: cmd.Examples,
+		RawArgs: cmd.RawArgs,
+		Timeout: manifestTimeout(cmd.Timeout),
+		Hidden: cmd.Hidden,
+		Deprecated: cmd.Deprecated,
+		Flags: newManifestFlags(cmd.Flags),
+		FlagGroups: cmd.FlagGroups,
+		InputSchema: InputSchema(inheritFlags(cmd.Flags, managers)),
+	}
+	return
+}
+
+// manifestTimeout is the function that returns the timeout of a command as text, empty if it has none.
+func manifestTimeout(d time.Duration) (t string) {
+	if d > 0 {
+		t = d.String()
+	}
+	return
+}
+
+// newManifestFlags is the function that returns the manifest of flags.
+func newManifestFlags(flags Flags) (fs []ManifestFlag) {
+	fs = []ManifestFlag{}
+	for _, f := range flags {
+		fs = append(fs, ManifestFlag{
+			Name: f.Name,
+			Description: f.Description,
+			Type: f.FlagType(),
+			Default: f.DefaultText(),
+			Required: f.Required,
+			Hidden: f.Hidden,
+			Deprecated: f.Deprecated,
+			Secret: f.Secret,
+			Enum: f.Enum,
+			Min: f.Min,
+			Max: f.Max,
+			Pattern: f.Pattern,
+			Exists: f.Exists,
+		})
+	}
+	return
+}
+
+// InputSchema is the function that returns the JSON Schema of the input of a command.
+// - the input is an object with a property per flag, typed after the type of the flag
+func InputSchema(flags Flags) (schema map[string]any) {
+	properties := make(map[string]any)
+	required := []string{}
+	for _, f := range flags {
+		property := map[string]any{}
+		switch f.FlagType() {
+		case FlagTypeInt:
+			property["type"] = "integer"
+		case FlagTypeFloat:
+			property["type"] = "number"
+		case FlagTypeBool:
+			property["type"] = "boolean"
+		case FlagTypeDuration:
+			property["type"] = "string"
+			property["format"] = "duration"
+		default:
+			property["type"] = "string"
+			// - custom types are described by the name of their type
+			if f.Value != nil {
+				property["format"] = string(f.FlagType())
+			}
+		}
+		if f.Description != "" {
+			property["description"] = f.Description
+		}
+		if f.Default != "" && !f.Secret {
+			property["default"] = schemaDefault(f.FlagType(), f.Default)
+		}
+		if f.Secret {
+			property["writeOnly"] = true
+		}
+		if len(f.Enum) > 0 {
+			enum := make([]any, 0, len(f.Enum))
+			for _, value := range f.Enum {
+				enum = append(enum, schemaDefault(f.FlagType(), value))
+			}
+			property["enum"] = enum
+		}
+		if f.Min != nil {
+			property["minimum"] = *f.Min
+		}
+		if f.Max != nil {
+			property["maximum"] = *f.Max
+		}
+		if f.Pattern != "" {
+			property["pattern"] = f.Pattern
+		}
+		if f.Deprecated != nil {
+			property["deprecated"] = true
+		}
+		properties[f.Name] = property
+
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	schema = map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": properties,
+		"required": required,
+		"additionalProperties": false,
+	}
+	return
+}
+
+// schemaDefault is the function that returns the default of a flag typed as in its schema.
+// - the default is kept as text if it does not match the type
+func schemaDefault(t FlagType, value string) (v any) {
+	v = value
+	switch t {
+	case FlagTypeInt:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			v = n
+		}
+	case FlagTypeFloat:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			v = n
+		}
+	case FlagTypeBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			v = b
+		}
+	case FlagTypeDuration:
+		if d, err := time.ParseDuration(value); err == nil {
+			v = d.String()
+		}
+	}
+	return
+}
+
+// EnableManifest is the method that adds the hidden command `__manifest`,
+// which writes the manifest of the command manager tree, e.g. `app __manifest --format json`.
+func (c *CommanderManager) EnableManifest() {
+	c.AddCommand(Command{
+		Name: "__manifest",
+		Description: "writes the manifest of the command tree",
+		Hidden: true,
+		Flags: Flags{
+			{Name: "format", Description: "format of the manifest", Default: "json"},
+		},
+		Handler: func(i Input) (err error) {
+			if format, ok := i.Flags["format"]; ok && fmt.Sprint(format) != "json" {
+				err = fmt.Errorf("%w: %q (expected json)", ErrManifestInvalidFormat, format)
+				return
+			}
+
+			w := i.Writer
+			if w == nil {
+				w = os.Stdout
+			}
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(NewManifest(c))
+			return
+		},
+	})
+}
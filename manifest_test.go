@@ -0,0 +1,129 @@
+package gocli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewManifest is the test for the function NewManifest.
+func TestNewManifest(t *testing.T) {
+	t.Run("success - case 01: manifest of a tree", func(t *testing.T) {
+		// arrange
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		db := cmg.Group("db", "database commands")
+		db.(gocli.GroupConfigurer).SetHidden(true)
+		db.AddCommand(gocli.Command{
+			Name:        "migrate",
+			Description: "runs the migrations",
+			Aliases:     []string{"m"},
+			Deprecated:  &gocli.Deprecated{Replacement: "db up"},
+			Flags: gocli.Flags{
+				{Name: "env", Description: "environment", Default: "dev", Required: true},
+				{Name: "steps", Type: gocli.FlagTypeInt, Default: "3"},
+			},
+		})
+
+		// act
+		m := gocli.NewManifest(cmg)
+		b, err := json.MarshalIndent(m, "", "  ")
+
+		// assert
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"version": "1",
+			"root": {
+				"name": "app",
+				"path": [],
+				"description": "app description",
+				"commands": [],
+				"groups": [
+					{
+						"name": "db",
+						"path": ["db"],
+						"description": "database commands",
+						"hidden": true,
+						"commands": [
+							{
+								"name": "migrate",
+								"path": ["db", "migrate"],
+								"description": "runs the migrations",
+								"aliases": ["m"],
+								"deprecated": {"replacement": "db up"},
+								"flags": [
+									{"name": "env", "description": "environment", "type": "string", "default": "dev", "required": true},
+									{"name": "steps", "type": "int", "default": "3"}
+								],
+								"input_schema": {
+									"$schema": "https://json-schema.org/draft/2020-12/schema",
+									"type": "object",
+									"properties": {
+										"env": {"type": "string", "description": "environment", "default": "dev"},
+										"steps": {"type": "integer", "default": 3}
+									},
+									"required": ["env"],
+									"additionalProperties": false
+								}
+							}
+						],
+						"groups": []
+					}
+				]
+			}
+		}`, string(b))
+	})
+}
+
+// TestCommanderManager_EnableManifest is the test for the method EnableManifest.
+func TestCommanderManager_EnableManifest(t *testing.T) {
+	t.Run("success - case 01: manifest is written as json", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "__manifest", "--format", "json"}
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.AddCommand(gocli.Command{Name: "version"})
+		cmg.EnableManifest()
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cmg)
+		cli.Writer = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		var m gocli.Manifest
+		require.NoError(t, json.Unmarshal(w.Bytes(), &m))
+		require.Equal(t, gocli.ManifestVersion, m.Version)
+		require.Len(t, m.Root.Commands, 2)
+		require.Equal(t, "__manifest", m.Root.Commands[1].Name)
+		require.True(t, m.Root.Commands[1].Hidden)
+	})
+
+	t.Run("failure - case 01: format is not supported", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "__manifest", "--format", "yaml"}
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.EnableManifest()
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cmg)
+		cli.Writer = &bytes.Buffer{}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrManifestInvalidFormat)
+	})
+}
@@ -0,0 +1,260 @@
+package gocli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrMCPNotSupported is the error that returns when the commander can not list its commands as tools.
+	ErrMCPNotSupported = errors.New("mcp not supported by commander")
+	// ErrMCPToolCollision is the error that returns when two commands have the same tool name, e.g. `a_b` and `a b`.
+	ErrMCPToolCollision = errors.New("mcp tool name collision")
+)
+
+// MCPProtocolVersion is the version of the Model Context Protocol supported by the server.
+// - it is the version answered to a client asking for another one
+const MCPProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes.
+const (
+	mcpParseError     = -32700
+	mcpMethodNotFound = -32601
+	mcpInvalidParams  = -32602
+)
+
+// mcpRequest is the struct that represents a JSON-RPC request or notification.
+type mcpRequest struct {
+	// JSONRPC is the version of JSON-RPC, always "2.0".
+	JSONRPC string `json:"jsonrpc"`
+	// ID is the id of the request, absent for a notification.
+	ID json.RawMessage `json:"id,omitempty"`
+	// Method is the method called.
+	Method string `json:"method"`
+	// Params are the params of the method.
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// mcpResponse is the struct that represents a JSON-RPC response.
+type mcpResponse struct {
+	// JSONRPC is the version of JSON-RPC, always "2.0".
+	JSONRPC string `json:"jsonrpc"`
+	// ID is the id of the request answered.
+	ID json.RawMessage `json:"id"`
+	// Result is the result of the method, if it succeeded.
+	Result any `json:"result,omitempty"`
+	// Error is the error of the method, if it failed.
+	Error *mcpError `json:"error,omitempty"`
+}
+
+// mcpError is the struct that represents a JSON-RPC error.
+type mcpError struct {
+	// Code is the code of the error.
+	Code int `json:"code"`
+	// Message is the message of the error.
+	Message string `json:"message"`
+}
+
+// mcpTool is the struct that represents a command exposed as an MCP tool.
+type mcpTool struct {
+	// Name is the name of the tool, the path of the command joined with underscores.
+	Name string `json:"name"`
+	// Description is the description of the command.
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON Schema of the arguments, built from the flags of the command.
+	InputSchema map[string]any `json:"inputSchema"`
+
+	// path is the chain of the command followed by its name.
+	path []string
+}
+
+// ServeMCP is the method that runs a Model Context Protocol server over a reader and a writer,
+// speaking newline-delimited JSON-RPC 2.0 (the MCP stdio transport).
+// - each non-hidden, non-local command without raw args is a tool, with an input schema built from its flag specs
+// - tool calls run through the same Commander, with their output captured
+// - it returns when the reader is exhausted or the context is done
+// - it fails at once if two commands have the same tool name
+// - a handler that does not return within the grace period after the context is done is left running,
+// its goroutine is only freed when it returns
+func (c CLI) ServeMCP(ctx context.Context, r io.Reader, w io.Writer) (err error) {
+	cm, ok := c.Commander.(*CommanderManager)
+	if !ok {
+		err = ErrMCPNotSupported
+		return
+	}
+	tools, err := mcpTools(cm)
+	if err != nil {
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		// request
+		var req mcpRequest
+		if jerr := json.Unmarshal(line, &req); jerr != nil {
+			err = enc.Encode(mcpResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &mcpError{Code: mcpParseError, Message: jerr.Error()}})
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		// notifications are not answered
+		result, rerr := c.handleMCP(ctx, cm, tools, req)
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		// response
+		resp := mcpResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}
+		err = enc.Encode(resp)
+		if err != nil {
+			return
+		}
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// handleMCP is the method that handles a JSON-RPC request.
+func (c CLI) handleMCP(ctx context.Context, cm *CommanderManager, tools []mcpTool, req mcpRequest) (result any, rerr *mcpError) {
+	switch req.Method {
+	case "initialize":
+		// the version is always the one supported, the client disconnects if it does not support it
+		result = map[string]any{
+			"protocolVersion": MCPProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": cm.Name, "version": c.Version},
+		}
+	case "ping":
+		result = map[string]any{}
+	case "tools/list":
+		result = map[string]any{"tools": tools}
+	case "tools/call":
+		result, rerr = c.callMCPTool(ctx, tools, req.Params)
+	default:
+		rerr = &mcpError{Code: mcpMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	return
+}
+
+// callMCPTool is the method that runs the command of a tool call, capturing its output.
+func (c CLI) callMCPTool(ctx context.Context, tools []mcpTool, raw json.RawMessage) (result any, rerr *mcpError) {
+	var params struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	}
+	// - numbers are kept as they are written, like in ServeHandler
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&params); err != nil {
+		rerr = &mcpError{Code: mcpInvalidParams, Message: err.Error()}
+		return
+	}
+
+	// tool
+	var tool *mcpTool
+	for _, t := range tools {
+		if t.Name == params.Name {
+			tool = &t
+			break
+		}
+	}
+	if tool == nil {
+		rerr = &mcpError{Code: mcpInvalidParams, Message: "unknown tool: " + params.Name}
+		return
+	}
+
+	// run
+	var stdout, stderr bytes.Buffer
+	input := Input{
+		CommandInput: CommandInput{Chain: tool.path[:len(tool.path)-1], Command: tool.path[len(tool.path)-1]},
+		Flags:        serveFlags(params.Arguments),
+		Args:         append([]string{}, tool.path...),
+		Context:      ctx,
+		Output:       OutputModeJSON,
+		Reader:       strings.NewReader(""),
+		Writer:       &stdout,
+		ErrWriter:    &stderr,
+		remote:       true,
+	}
+	err := c.execute(input, 0)
+
+	// result
+	text := stdout.String()
+	if stderr.Len() > 0 {
+		text += stderr.String()
+	}
+	if err != nil {
+		text += err.Error()
+	}
+	result = map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": err != nil,
+	}
+	return
+}
+
+// mcpTools is the function that returns the non-hidden, non-local commands of a tree as tools.
+// - the name of a tool is the path of its command joined with underscores, two commands with the same name are an error
+// - commands with raw args are not tools, like in ServeHandler, since they run the args given by the client as is, e.g. plugins
+func mcpTools(cm *CommanderManager) (tools []mcpTool, err error) {
+	tools = []mcpTool{}
+	paths := make(map[string][]string)
+	var walk func(g *CommanderManager, path []string, managers []*CommanderManager)
+	walk = func(g *CommanderManager, path []string, managers []*CommanderManager) {
+		managers = append(append([]*CommanderManager{}, managers...), g)
+		for _, cmd := range g.Cmds {
+			if cmd.Hidden || cmd.Local || cmd.RawArgs {
+				continue
+			}
+			p := append(append([]string{}, path...), cmd.Name)
+
+			// input schema
+			// - including the persistent flags of the groups
+			var flags Flags
+			for _, f := range inheritFlags(cmd.Flags, managers) {
+				if !f.Hidden {
+					flags = append(flags, f)
+				}
+			}
+			schema := InputSchema(flags)
+			delete(schema, "$schema")
+
+			name := strings.Join(p, "_")
+			if other, ok := paths[name]; ok && err == nil {
+				err = fmt.Errorf("%w: %s (%q and %q)", ErrMCPToolCollision, name, strings.Join(other, " "), strings.Join(p, " "))
+			}
+			paths[name] = p
+			tools = append(tools, mcpTool{Name: name, Description: cmd.Description, InputSchema: schema, path: p})
+		}
+		for _, sub := range g.CommandManagers {
+			if sub.Hidden {
+				continue
+			}
+			walk(sub, append(append([]string{}, path...), sub.Name), managers)
+		}
+	}
+	walk(cm, []string{}, nil)
+	if err != nil {
+		tools = nil
+	}
+	return
+}
@@ -0,0 +1,216 @@
+package gocli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_ServeMCP is the test for the method ServeMCP.
+func TestCLI_ServeMCP(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func() (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		deploy := cm.Group("deploy", "deploy commands")
+		deploy.AddCommand(gocli.Command{
+			Name:        "status",
+			Description: "shows the status of a deploy",
+			Flags: gocli.Flags{
+				{Name: "env", Description: "environment", Required: true},
+				{Name: "limit", Type: gocli.FlagTypeInt, Default: "10"},
+			},
+			Handler: func(i gocli.Input) (err error) {
+				fmt.Fprintf(i.Writer, "env=%v limit=%v", i.Flags["env"], i.Flags["limit"])
+				return
+			},
+		})
+		deploy.AddCommand(gocli.Command{
+			Name: "fail",
+			Handler: func(i gocli.Input) (err error) {
+				err = errors.New("deploy failed")
+				return
+			},
+		})
+		deploy.AddCommand(gocli.Command{Name: "secret", Hidden: true, Handler: func(i gocli.Input) (err error) { return }})
+		deploy.AddCommand(gocli.Command{Name: "forget", Local: true, Handler: func(i gocli.Input) (err error) { return }})
+		deploy.AddCommand(gocli.Command{Name: "exec", RawArgs: true, Handler: func(i gocli.Input) (err error) { return }})
+		cli = gocli.NewCLI(nil, cm)
+		cli.Version = "1.0.0"
+		return
+	}
+
+	// serve is the function that serves the requests and decodes the responses
+	serve := func(t *testing.T, cli gocli.CLI, requests ...string) (responses []map[string]any) {
+		var out bytes.Buffer
+		err := cli.ServeMCP(context.Background(), strings.NewReader(strings.Join(requests, "\n")), &out)
+		require.NoError(t, err)
+
+		dec := json.NewDecoder(&out)
+		for dec.More() {
+			var resp map[string]any
+			require.NoError(t, dec.Decode(&resp))
+			responses = append(responses, resp)
+		}
+		return
+	}
+
+	t.Run("success - case 01: initialize and notifications", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+
+		// act
+		// - the client asks for a version not supported
+		responses := serve(t, cli,
+			`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2099-01-01"}}`,
+			`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+			`{"jsonrpc": "2.0", "id": 2, "method": "ping"}`,
+		)
+
+		// assert
+		require.Len(t, responses, 2)
+		require.Equal(t, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      float64(1),
+			"result": map[string]any{
+				"protocolVersion": "2024-11-05",
+				"capabilities":    map[string]any{"tools": map[string]any{}},
+				"serverInfo":      map[string]any{"name": "app", "version": "1.0.0"},
+			},
+		}, responses[0])
+		require.Equal(t, map[string]any{"jsonrpc": "2.0", "id": float64(2), "result": map[string]any{}}, responses[1])
+	})
+
+	t.Run("success - case 02: non-hidden, non-local commands without raw args are listed as tools", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+
+		// act
+		responses := serve(t, cli, `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+
+		// assert
+		tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+		require.Len(t, tools, 2)
+		status := tools[0].(map[string]any)
+		require.Equal(t, "deploy_status", status["name"])
+		require.Equal(t, "shows the status of a deploy", status["description"])
+		schema := status["inputSchema"].(map[string]any)
+		require.Equal(t, "object", schema["type"])
+		require.Equal(t, []any{"env"}, schema["required"])
+		require.Contains(t, schema["properties"], "limit")
+		require.Equal(t, "deploy_fail", tools[1].(map[string]any)["name"])
+	})
+
+	t.Run("success - case 03: tool call runs the command with its output captured", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+
+		// act
+		responses := serve(t, cli, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "deploy_status", "arguments": {"env": "prod"}}}`)
+
+		// assert
+		require.Equal(t, map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": "env=prod limit=10"}},
+			"isError": false,
+		}, responses[0]["result"])
+	})
+
+	t.Run("success - case 04: tool call errors are reported as results", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+
+		// act
+		responses := serve(t, cli,
+			`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "deploy_fail"}}`,
+			`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "deploy_status"}}`,
+		)
+
+		// assert
+		require.Equal(t, map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": "deploy failed"}},
+			"isError": true,
+		}, responses[0]["result"])
+		require.Equal(t, true, responses[1]["result"].(map[string]any)["isError"])
+		require.Contains(t, responses[1]["result"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"], "--env")
+	})
+
+	t.Run("success - case 05: alias set and delete are not tools", func(t *testing.T) {
+		// arrange
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnableAliases(t.TempDir() + "/config")
+		cli := gocli.NewCLI(nil, cm)
+
+		// act
+		responses := serve(t, cli, `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+
+		// assert
+		tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+		require.Len(t, tools, 1)
+		require.Equal(t, "alias_list", tools[0].(map[string]any)["name"])
+	})
+
+	t.Run("success - case 06: large numbers are given as written", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+
+		// act
+		responses := serve(t, cli, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "deploy_status", "arguments": {"env": "prod", "limit": 12345678901234567}}}`)
+
+		// assert
+		require.Equal(t, map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": "env=prod limit=12345678901234567"}},
+			"isError": false,
+		}, responses[0]["result"])
+	})
+
+	t.Run("failure - case 01: unknown method and tool", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+
+		// act
+		responses := serve(t, cli,
+			`{"jsonrpc": "2.0", "id": 1, "method": "resources/list"}`,
+			`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "deploy_secret"}}`,
+			`not json`,
+			`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "deploy_forget"}}`,
+		)
+
+		// assert
+		require.Len(t, responses, 4)
+		require.Equal(t, float64(-32601), responses[0]["error"].(map[string]any)["code"])
+		require.Equal(t, float64(-32602), responses[1]["error"].(map[string]any)["code"])
+		require.Equal(t, float64(-32700), responses[2]["error"].(map[string]any)["code"])
+		require.Nil(t, responses[2]["id"])
+		require.Equal(t, float64(-32602), responses[3]["error"].(map[string]any)["code"])
+	})
+
+	t.Run("failure - case 02: two commands have the same tool name", func(t *testing.T) {
+		// arrange
+		cli := newCLI()
+		cli.Commander.AddCommand(gocli.Command{Name: "deploy_status", Handler: func(i gocli.Input) (err error) { return }})
+
+		// act
+		err := cli.ServeMCP(context.Background(), strings.NewReader(""), &bytes.Buffer{})
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrMCPToolCollision)
+		require.EqualError(t, err, `mcp tool name collision: deploy_status ("deploy_status" and "deploy status")`)
+	})
+
+	t.Run("failure - case 03: commander not supported", func(t *testing.T) {
+		// arrange
+		cli := gocli.NewCLI(nil, gocli.NewCommanderMock())
+
+		// act
+		err := cli.ServeMCP(context.Background(), strings.NewReader(""), &bytes.Buffer{})
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrMCPNotSupported)
+	})
+}
@@ -0,0 +1,35 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidOutputMode is the error that returns when the output mode is invalid.
+	ErrInvalidOutputMode = errors.New("invalid output mode")
+)
+
+// OutputMode is the type that represents the format of the output of the commands.
+type OutputMode string
+
+const (
+	// OutputModeText is the human readable output.
+	OutputModeText OutputMode = "text"
+	// OutputModeJSON is the machine readable output.
+	// - decorations such as progress bars and spinners are not rendered
+	OutputModeJSON OutputMode = "json"
+)
+
+// ParseOutputMode is the function that parses an output mode, as given to the --output flag.
+func ParseOutputMode(value string) (m OutputMode, err error) {
+	switch OutputMode(value) {
+	case OutputModeText, OutputModeJSON:
+		m = OutputMode(value)
+	case "":
+		m = OutputModeText
+	default:
+		err = fmt.Errorf("%w: %q (expected text or json)", ErrInvalidOutputMode, value)
+	}
+	return
+}
@@ -1,6 +1,11 @@
 package gocli
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
 
 var (
 	// ErrInvalidArgs is the error that occurs when the command is invalid.
@@ -24,9 +29,49 @@ type Input struct {
 	Flags map[string]any
 	// Options are the options of the command.
 	Options map[string]int
+
+	// Args are the args of the command line, without the app name and the global flags, set by the CLI.
+	Args []string
+	// Context is the context of the execution, set by the CLI.
+	Context context.Context
+	// Output is the output mode requested with the --output flag, set by the CLI.
+	Output OutputMode
+	// Reader is the reader of the user input, set by the CLI.
+	Reader io.Reader
+	// Writer is the writer of the output, set by the CLI.
+	Writer io.Writer
+	// ErrWriter is the writer of the errors and prompts, set by the CLI.
+	ErrWriter io.Writer
+	// Interactive is the flag that indicates if the reader is a terminal, set by the CLI.
+	// - prompts fail when it is false
+	Interactive bool
+	// Style is the styler of the output, set by the CLI.
+	// - it is disabled when the output is not a terminal, NO_COLOR is set or --color=never is given
+	Style Styler
+	// Logger is the logger of the command, set by the CLI.
+	// - it writes to the error writer, with the command chain and the run id as attributes
+	// - its level is controlled by the -v, -vv, --quiet and --log-level flags
+	Logger *slog.Logger
+
+	// given are the names of the flags given in the args, without the defaults applied, set by the CLI.
+	// - nil if the input was not made by the CLI, every flag is then taken as given
+	given map[string]bool
+	// specs is the specification of the flags of the command, with the persistent flags of its groups, set by the CLI.
+	// - nil if the commander does not support it, see CommandPathFinder
+	specs Flags
+	// remote is the flag that indicates if the flags were given by a client, set by ServeHandler and ServeMCP.
+	// - the values of the secret flags are then never read from files, see readSecretFlags
+	remote bool
 }
 
 // Parser is the interface that wraps the basic Parse method.
 type Parser interface {
 	Parse(args string) (i Input, err error)
-}
\ No newline at end of file
+}
+
+// PersistentParser is the interface that wraps the ParsePersistent method.
+// - it is optional, the CLI uses it to give the parser the persistent flags of the groups of the command,
+// which may be given within the chain, e.g. `app --profile x db migrate`
+type PersistentParser interface {
+	ParsePersistent(args string, persistent map[string]bool) (i Input, err error)
+}
@@ -25,9 +25,9 @@ type ConfigParserDefault struct {
 func NewParserDefault(cfg optional.Option[ConfigParserDefault]) (p *ParserDefault) {
 	// default configuration
 	defaultCfg := ConfigParserDefault{
-		PatternCLI: `^(\w+(?:\s+\w+)*)(\s+-{1,2}\w+\s+\w+)*(\s+-[A-Z0-9]+)*$`,
+		PatternCLI: `^(\w+(?:\s+\w+)*)(\s+-{1,2}\w[\w-]*\s+(?:[^\s-]\S*|-\B))*(\s+-[A-Z0-9]+)*$`,
 		PatternChain: `^(\w+(?:\s+\w+)*)`,
-		PatternFlag: `(\s+-{1,2}\w+\s+\w+)+`,
+		PatternFlag: `(\s+-{1,2}\w[\w-]*\s+(?:[^\s-]\S*|-\B))+`,
 		PatternOption: `(\s+-[A-Z0-9]+)+`,
 		Trimmer: `\s{2,}`,
 	}
@@ -63,13 +63,13 @@ func NewParserDefault(cfg optional.Option[ConfigParserDefault]) (p *ParserDefaul
 // ParserDefault is the struct that wraps the default parser.
 type ParserDefault struct {
 	// patternCLI is the regexp pattern of the full command line.
-	// - default: `^(\w+(?:\s+\w+)*)(\s+-{1,2}\w+\s+\w+)*(\s+-[A-Z0-9]+)*$`
+	// - default: `^(\w+(?:\s+\w+)*)(\s+-{1,2}\w[\w-]*\s+(?:[^\s-]\S*|-\B))*(\s+-[A-Z0-9]+)*$`
 	patternCLI *regexp.Regexp
 	// patternCommand is the regexp pattern of the command.
 	// - default: `^(\w+(?:\s+\w+)*)`
 	patternCommand *regexp.Regexp
 	// patternFlags is the regexp pattern of the flag.
-	// - default: `(\s+-{1,2}\w+\s+\w+)+`
+	// - default: `(\s+-{1,2}\w[\w-]*\s+(?:[^\s-]\S*|-\B))+`
 	patternFlag *regexp.Regexp
 	// patternOptions is the regexp pattern of the option.
 	// - default: `(\s+-[A-Z0-9]+)+`
@@ -80,7 +80,19 @@ type ParserDefault struct {
 }
 
 // Parse is the method that parses the input.
+// - a flag value is a word not starting with -, or a lone - (e.g. `--token-file -` for stdin)
+// - flags are expected after the chain, see ParsePersistent for the flags given within it
 func (p *ParserDefault) Parse(args string) (i Input, err error) {
+	i, err = p.ParsePersistent(args, nil)
+	return
+}
+
+// ParsePersistent is the method that parses the input, with the persistent flags of the groups of the command.
+// - persistent flags may be given in any position of the chain, e.g. `app --profile x db migrate`
+func (p *ParserDefault) ParsePersistent(args string, persistent map[string]bool) (i Input, err error) {
+	// persistent flags are moved after the chain
+	args = p.Normalize(args, persistent)
+
 	// check matching between args and patternCLI
 	if !p.patternCLI.MatchString(args) {
 		err = ErrInvalidArgs
@@ -103,6 +115,42 @@ func (p *ParserDefault) Parse(args string) (i Input, err error) {
 	return
 }
 
+// Normalize is the method that moves the persistent flags given within the chain after it.
+// - the other words and flags keep their order, then the persistent flags, then the options
+// - a flag is followed by its value, an option (e.g. -O1) has no value
+// - a flag that is not persistent is left in place, so a flag within the chain is still rejected
+// - a flag without a value is left at the end, so the args are still rejected
+func (p *ParserDefault) Normalize(args string, persistent map[string]bool) (r string) {
+	fields := strings.Fields(args)
+	var words, flags, options []string
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case !strings.HasPrefix(field, "-"):
+			words = append(words, field)
+		case p.isOption(field):
+			options = append(options, field)
+		case i+1 >= len(fields):
+			options = append(options, field)
+		case persistent[strings.TrimLeft(field, "-")]:
+			flags = append(flags, field, fields[i+1])
+			i++
+		default:
+			words = append(words, field, fields[i+1])
+			i++
+		}
+	}
+
+	r = strings.Join(append(append(words, flags...), options...), " ")
+	return
+}
+
+// isOption is the method that checks if a single arg is an option, with the pattern of the options.
+func (p *ParserDefault) isOption(arg string) (ok bool) {
+	ok = p.patternOption.FindString(" "+arg) == " "+arg
+	return
+}
+
 // ParseCommands is the method that parses the commands.
 func (p *ParserDefault) ParseCommands(args string) (c CommandInput, err error) {
 	// check matching between args and patternCommand
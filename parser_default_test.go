@@ -281,6 +281,111 @@ func TestParserDefault_Parse(t *testing.T) {
 		}, i)
 	})
 
+	t.Run("success - case 04: + 1 command + flag with dashes and a path value", func(t *testing.T) {
+		// arrange
+		// - parser: default
+		// - args: login --token-file /run/secrets/token.txt -O1
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+
+		// act
+		args := "login --token-file /run/secrets/token.txt -O1"
+		i, err := ps.Parse(args)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, gocli.Input{
+			CommandInput: gocli.CommandInput{
+				Chain: []string{},
+				Command: "login",
+			},
+			Flags: map[string]any{
+				"token-file": "/run/secrets/token.txt",
+			},
+			Options: map[string]int{
+				"O1": 1,
+			},
+		}, i)
+	})
+
+	t.Run("success - case 05: + 2 chain + 1 command + persistent flags within the chain", func(t *testing.T) {
+		// arrange
+		// - parser: default
+		// - args: app --profile prod db --dry-run true migrate -O1
+		// - persistent: profile, dry-run
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+
+		// act
+		args := "app --profile prod db --dry-run true migrate -O1"
+		i, err := ps.ParsePersistent(args, map[string]bool{"profile": true, "dry-run": true})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, gocli.Input{
+			CommandInput: gocli.CommandInput{
+				Chain: []string{"app", "db"},
+				Command: "migrate",
+			},
+			Flags: map[string]any{
+				"profile": "prod",
+				"dry-run": "true",
+			},
+			Options: map[string]int{
+				"O1": 1,
+			},
+		}, i)
+	})
+
+	t.Run("success - case 06: + 1 command + flag with - as value", func(t *testing.T) {
+		// arrange
+		// - parser: default
+		// - args: login --token-file - -O1
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+
+		// act
+		args := "login --token-file - -O1"
+		i, err := ps.Parse(args)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, gocli.Input{
+			CommandInput: gocli.CommandInput{
+				Chain: []string{},
+				Command: "login",
+			},
+			Flags: map[string]any{
+				"token-file": "-",
+			},
+			Options: map[string]int{
+				"O1": 1,
+			},
+		}, i)
+	})
+
+	t.Run("success - case 07: inputs of the previous default patterns parse the same", func(t *testing.T) {
+		// arrange
+		// - parser: default
+		// - args: the previous default patterns were `(\s+-{1,2}\w+\s+\w+)`
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+		cases := []struct {
+			args string
+			input gocli.Input
+		}{
+			{"cmd1", gocli.Input{CommandInput: gocli.CommandInput{Chain: []string{}, Command: "cmd1"}}},
+			{"cmd1 -f v1", gocli.Input{CommandInput: gocli.CommandInput{Chain: []string{}, Command: "cmd1"}, Flags: map[string]any{"f": "v1"}}},
+			{"cmd1 --flag1 value1 -O1", gocli.Input{CommandInput: gocli.CommandInput{Chain: []string{}, Command: "cmd1"}, Flags: map[string]any{"flag1": "value1"}, Options: map[string]int{"O1": 1}}},
+			{"chain1 cmd1 --flag_1 1 --flag2 value2 -O1 -O2", gocli.Input{CommandInput: gocli.CommandInput{Chain: []string{"chain1"}, Command: "cmd1"}, Flags: map[string]any{"flag_1": "1", "flag2": "value2"}, Options: map[string]int{"O1": 1, "O2": 1}}},
+		}
+
+		for _, c := range cases {
+			// act
+			i, err := ps.Parse(c.args)
+
+			// assert
+			require.NoError(t, err, c.args)
+			require.Equal(t, c.input, i, c.args)
+		}
+	})
+
 	t.Run("failure - case 01: + 1 flag + 1 option | invalid args", func(t *testing.T) {
 		// arrange
 		// - parser: default
@@ -331,4 +436,66 @@ func TestParserDefault_Parse(t *testing.T) {
 		require.EqualError(t, err, gocli.ErrInvalidArgs.Error())
 		require.Equal(t, gocli.Input{}, i)
 	})
-}
\ No newline at end of file
+}
+// TestParserDefault_Normalize tests the method Normalize of the ParserDefault type.
+func TestParserDefault_Normalize(t *testing.T) {
+	t.Run("success - case 01: flags and options after the chain are kept", func(t *testing.T) {
+		// arrange
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+
+		// act
+		args := ps.Normalize("db migrate --env prod -O1", nil)
+
+		// assert
+		require.Equal(t, "db migrate --env prod -O1", args)
+	})
+
+	t.Run("success - case 02: persistent flags within the chain are moved after it", func(t *testing.T) {
+		// arrange
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+		persistent := map[string]bool{"profile": true, "region": true}
+
+		// act
+		args := ps.Normalize("--profile prod db -O1 --region eu migrate --env prod", persistent)
+
+		// assert
+		require.Equal(t, "db migrate --env prod --profile prod --region eu -O1", args)
+	})
+
+	t.Run("success - case 03: a flag without a value is left at the end", func(t *testing.T) {
+		// arrange
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+
+		// act
+		args := ps.Normalize("db migrate --env", nil)
+
+		// assert
+		require.Equal(t, "db migrate --env", args)
+	})
+
+	t.Run("success - case 04: flags that are not persistent are left in place", func(t *testing.T) {
+		// arrange
+		ps := gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]())
+		persistent := map[string]bool{"profile": true}
+
+		// act
+		args := ps.Normalize("deploy --msg hello world", persistent)
+
+		// assert
+		require.Equal(t, "deploy --msg hello world", args)
+	})
+
+	t.Run("success - case 05: options are matched with the configured pattern", func(t *testing.T) {
+		// arrange
+		ps := gocli.NewParserDefault(optional.Some(gocli.ConfigParserDefault{
+			PatternCLI: `^(\w+(?:\s+\w+)*)(\s+-{1,2}\w+\s+\w+)*(\s+-[a-z]+)*$`,
+			PatternOption: `(\s+-[a-z]+)+`,
+		}))
+
+		// act
+		args := ps.Normalize("db -dry migrate --env prod", nil)
+
+		// assert
+		require.Equal(t, "db migrate --env prod -dry", args)
+	})
+}
@@ -0,0 +1,245 @@
+package gocli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Plugin is the struct that represents an external plugin command.
+type Plugin struct {
+	// Name is the name of the command, e.g. "foo" for the executable "app-foo".
+	Name string
+	// Path is the path of the executable.
+	Path string
+}
+
+// Plugins is the struct that discovers external plugin commands, the way `git foo` runs `git-foo`.
+// - the executables are named <Prefix>-<command>
+type Plugins struct {
+	// Prefix is the prefix of the executables, usually the name of the app.
+	Prefix string
+	// Dirs are the plugin directories, searched before the directories of PATH.
+	Dirs []string
+
+	// cache is the cache of the plugins found during a run, set by EnablePlugins.
+	cache *pluginCache
+}
+
+// pluginCache is the struct that caches the plugins found during a run.
+// - a run looks up the same command several times, e.g. for the raw args, the aliases and the handler
+// - it is cleared at the start of each run, so the plugins installed meanwhile are found, e.g. with ServeDaemon
+type pluginCache struct {
+	mu    sync.Mutex
+	found map[string]Plugin
+}
+
+// Find is the method that finds the plugin of a command.
+// - the first executable found wins, following the order of Dirs and then PATH
+// - the result is cached until the next run, see pluginCache
+func (p Plugins) Find(commandName string) (plugin Plugin, ok bool) {
+	if commandName == "" || strings.ContainsAny(commandName, `/\`) {
+		return
+	}
+
+	// cache
+	if p.cache != nil {
+		p.cache.mu.Lock()
+		found, cached := p.cache.found[commandName]
+		p.cache.mu.Unlock()
+		if cached {
+			plugin = found
+			ok = found.Path != ""
+			return
+		}
+	}
+
+	plugin, ok = p.find(commandName)
+	if p.cache != nil {
+		p.cache.mu.Lock()
+		if p.cache.found == nil {
+			p.cache.found = make(map[string]Plugin)
+		}
+		p.cache.found[commandName] = plugin
+		p.cache.mu.Unlock()
+	}
+	return
+}
+
+// find is the method that searches the dirs for the plugin of a command.
+func (p Plugins) find(commandName string) (plugin Plugin, ok bool) {
+	for _, dir := range p.dirs() {
+		for _, name := range p.executableNames(commandName) {
+			path := filepath.Join(dir, name)
+			if isExecutable(path) {
+				plugin = Plugin{Name: commandName, Path: path}
+				ok = true
+				return
+			}
+		}
+	}
+	return
+}
+
+// clearCache is the method that forgets the plugins found, at the start of a run.
+func (p Plugins) clearCache() {
+	if p.cache == nil {
+		return
+	}
+
+	p.cache.mu.Lock()
+	p.cache.found = nil
+	p.cache.mu.Unlock()
+}
+
+// List is the method that returns the plugins discovered, sorted by name.
+// - a plugin shadowed by another one with the same name is not listed
+func (p Plugins) List() (plugins []Plugin) {
+	seen := make(map[string]bool)
+	for _, dir := range p.dirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), p.Prefix+"-")
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, ".exe")
+			}
+			path := filepath.Join(dir, entry.Name())
+			if name == "" || seen[name] || !isExecutable(path) {
+				continue
+			}
+
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return
+}
+
+// Command is the method that returns the command that runs a plugin.
+// - the args after the command name, stdin, stdout, stderr and the environment are forwarded
+// - the exit code of the plugin is returned as an ExitError, 128+n if it was killed by the signal n, like shells do
+func (p Plugins) Command(plugin Plugin) (cmd Command) {
+	cmd = Command{
+		Name: plugin.Name,
+		Description: "external plugin " + plugin.Path,
+		RawArgs: true,
+		Handler: func(i Input) (err error) {
+			// args
+			args := i.Args
+			if len(args) > 0 {
+				args = args[1:]
+			}
+
+			// run
+			ctx := i.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			c := exec.CommandContext(ctx, plugin.Path, args...)
+			c.Stdin = i.Reader
+			c.Stdout = i.Writer
+			c.Stderr = i.ErrWriter
+			c.Env = append(os.Environ(), "GOCLI_PLUGIN_PREFIX="+p.Prefix)
+			err = c.Run()
+
+			// exit code
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code := exitErr.ExitCode()
+				if signal, ok := pluginSignal(exitErr); ok {
+					code = 128 + signal
+				}
+				err = &ExitError{Code: code}
+				return
+			}
+			if err != nil {
+				err = fmt.Errorf("plugin %s: %w", plugin.Name, err)
+			}
+			return
+		},
+	}
+	return
+}
+
+// dirs is the method that returns the directories where the plugins are searched.
+func (p Plugins) dirs() (dirs []string) {
+	dirs = append(dirs, p.Dirs...)
+	// - empty and relative entries of PATH are skipped, the current dir is never searched (see exec.ErrDot)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || !filepath.IsAbs(dir) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return
+}
+
+// executableNames is the method that returns the possible file names of the plugin of a command.
+func (p Plugins) executableNames(commandName string) (names []string) {
+	names = []string{p.Prefix + "-" + commandName}
+	if runtime.GOOS == "windows" {
+		names = append(names, p.Prefix+"-"+commandName+".exe")
+	}
+	return
+}
+
+// isExecutable is the function that checks if a path is an executable file.
+func isExecutable(path string) (ok bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return
+	}
+
+	ok = runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+	return
+}
+
+// EnablePlugins is the method that enables the external plugin commands of the command manager.
+// - an unknown top-level command runs the executable <name>-<command> found in the dirs or PATH
+// - the command `plugins list` shows the plugins discovered
+func (c *CommanderManager) EnablePlugins(dirs ...string) {
+	(*c).Plugins = &Plugins{Prefix: c.Name, Dirs: dirs, cache: &pluginCache{}}
+
+	group := c.Group("plugins", "manages the external plugin commands")
+	group.AddCommand(Command{
+		Name: "list",
+		Description: "lists the external plugin commands discovered",
+		Handler: func(i Input) (err error) {
+			w := i.Writer
+			if w == nil {
+				w = os.Stdout
+			}
+
+			plugins := c.Plugins.List()
+			if len(plugins) == 0 {
+				_, err = fmt.Fprintln(w, "no plugins found")
+				return
+			}
+			rows := make([][2]string, 0, len(plugins))
+			for _, plugin := range plugins {
+				rows = append(rows, [2]string{plugin.Name, plugin.Path})
+			}
+			var b strings.Builder
+			writeHelpSection(&b, i.Style, "Plugins:", rows)
+			_, err = fmt.Fprint(w, strings.TrimPrefix(b.String(), "\n"))
+			return
+		},
+	})
+	group.(GroupConfigurer).SetDefault("list")
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package gocli
+
+import "os/exec"
+
+// pluginSignal is the function that returns the signal that killed a plugin.
+// - processes are not killed by signals on this platform, ok is always false
+func pluginSignal(exitErr *exec.ExitError) (signal int, ok bool) {
+	return
+}
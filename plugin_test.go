@@ -0,0 +1,237 @@
+package gocli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin is the function that writes an executable shell script in the dir.
+func writePlugin(t *testing.T, dir, name, script string) (path string) {
+	t.Helper()
+	path = filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755)
+	require.NoError(t, err)
+	return
+}
+
+// TestPlugins is the test for the external plugin commands.
+func TestPlugins(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("plugins tests require /bin/sh")
+	}
+
+	t.Run("success - case 01: unknown command runs the plugin with the args", func(t *testing.T) {
+		// arrange
+		// - plugins
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-hello", `echo "hello $@"; read line; echo "read $line"`)
+		// - std-in
+		os.Args = []string{"app.exe", "hello", "--name", "gopher"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Reader = bytes.NewBufferString("input\n")
+		cli.Writer = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "hello --name gopher\nread input\n", w.String())
+	})
+
+	t.Run("success - case 02: args rejected by the parser are forwarded", func(t *testing.T) {
+		// arrange
+		// - plugins
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-hello", `echo "hello $@"`)
+		// - std-in
+		os.Args = []string{"app.exe", "hello", "file.txt", "-v"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Writer = w
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "hello file.txt -v\n", w.String())
+	})
+
+	t.Run("success - case 03: plugins list", func(t *testing.T) {
+		// arrange
+		// - plugins
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-zeta", "true")
+		writePlugin(t, dir, "app-alpha", "true")
+		err := os.WriteFile(filepath.Join(dir, "app-data"), []byte("not executable"), 0o644)
+		require.NoError(t, err)
+		// - std-in
+		os.Args = []string{"app.exe", "plugins", "list"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Writer = w
+
+		// act
+		err = cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Plugins:\n"+
+			"  alpha  "+filepath.Join(dir, "app-alpha")+"\n"+
+			"  zeta   "+filepath.Join(dir, "app-zeta")+"\n", w.String())
+	})
+
+	t.Run("success - case 04: global flags before the plugin are read, the ones after it are forwarded", func(t *testing.T) {
+		// arrange
+		// - plugins
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-hello", `echo "hello $@"`)
+		// - std-in
+		os.Args = []string{"app.exe", "--color", "never", "-v", "hello", "--color", "always"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Writer = w
+		cli.ErrWriter = &bytes.Buffer{}
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "hello --color always\n", w.String())
+	})
+
+	t.Run("success - case 05: relative dirs of PATH are not searched", func(t *testing.T) {
+		// arrange
+		// - plugin in the current dir, which is in PATH as an empty and a relative entry
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-hello", `echo "hello"`)
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir))
+		t.Cleanup(func() { os.Chdir(wd) })
+		t.Setenv("PATH", string(os.PathListSeparator)+"."+string(os.PathListSeparator)+os.Getenv("PATH"))
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins()
+
+		// act
+		_, ok := cm.Plugins.Find("hello")
+
+		// assert
+		require.False(t, ok)
+	})
+
+	t.Run("success - case 06: plugin installed after a run is found by the next run", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "hello"}
+		// - commander
+		dir := t.TempDir()
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		w := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Writer = w
+		cli.ErrWriter = &bytes.Buffer{}
+		errBefore := cli.Run()
+		writePlugin(t, dir, "app-hello", `echo "hello"`)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, errBefore, gocli.ErrCommandHandlerNotFound)
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", w.String())
+	})
+
+	t.Run("failure - case 01: exit code of the plugin is propagated", func(t *testing.T) {
+		// arrange
+		// - plugins
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-fail", "exit 3")
+		// - std-in
+		os.Args = []string{"app.exe", "fail"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		errW := &bytes.Buffer{}
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = errW
+
+		// act
+		err := cli.Run()
+		cli.RenderError(err)
+
+		// assert
+		require.Error(t, err)
+		require.Equal(t, 3, gocli.ExitCode(err))
+		require.Empty(t, errW.String())
+	})
+
+	t.Run("failure - case 02: no plugin for the command", func(t *testing.T) {
+		// arrange
+		// - std-in
+		os.Args = []string{"app.exe", "missing"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(t.TempDir())
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrCommandHandlerNotFound)
+		require.Equal(t, 1, gocli.ExitCode(err))
+	})
+	t.Run("failure - case 03: plugin killed by a signal exits with 128+signal", func(t *testing.T) {
+		// arrange
+		// - plugins
+		dir := t.TempDir()
+		writePlugin(t, dir, "app-killed", "kill -TERM $$")
+		// - std-in
+		os.Args = []string{"app.exe", "killed"}
+		// - commander
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.EnablePlugins(dir)
+		// - cli
+		cli := gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.Error(t, err)
+		require.Equal(t, 128+15, gocli.ExitCode(err))
+	})
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package gocli
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// pluginSignal is the function that returns the signal that killed a plugin.
+// - ok is false if the plugin exited on its own
+func pluginSignal(exitErr *exec.ExitError) (signal int, ok bool) {
+	status, isStatus := exitErr.Sys().(syscall.WaitStatus)
+	if !isStatus || !status.Signaled() {
+		return
+	}
+
+	signal = int(status.Signal())
+	ok = true
+	return
+}
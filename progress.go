@@ -0,0 +1,376 @@
+package gocli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LNMMusic/optional"
+)
+
+// ProgressMode is the type that represents how a progress is rendered.
+type ProgressMode string
+
+const (
+	// ProgressModeAuto renders on a terminal, logs otherwise and is silent for machine readable output.
+	ProgressModeAuto ProgressMode = ""
+	// ProgressModeTerminal redraws the bars in place.
+	ProgressModeTerminal ProgressMode = "terminal"
+	// ProgressModeLog writes periodic log lines.
+	ProgressModeLog ProgressMode = "log"
+	// ProgressModeSilent renders nothing.
+	ProgressModeSilent ProgressMode = "silent"
+)
+
+// spinnerFrames are the frames of a spinner.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// ConfigProgress is the struct that wraps the configuration of a progress.
+type ConfigProgress struct {
+	// Mode is the rendering mode of the progress.
+	// - default: auto
+	Mode ProgressMode
+	// Interval is the refresh interval of the terminal rendering.
+	// - default: 100ms
+	Interval time.Duration
+	// LogInterval is the interval between log lines when the writer is not a terminal.
+	// - default: 5s
+	LogInterval time.Duration
+	// Width is the width of the bars.
+	// - default: 30
+	Width int
+}
+
+// NewProgress is the function that returns a new progress rendered on the writer.
+// - the rendering stops when the context is done or when Stop is called
+// - in auto mode the progress is silent for the json output, and writes log lines if w is not a terminal
+func NewProgress(ctx context.Context, w io.Writer, output OutputMode, cfg optional.Option[ConfigProgress]) (p *Progress) {
+	// default configuration
+	defaultCfg := ConfigProgress{
+		Mode: ProgressModeAuto,
+		Interval: 100 * time.Millisecond,
+		LogInterval: 5 * time.Second,
+		Width: 30,
+	}
+	if cfg.IsSome() {
+		config := cfg.Unwrap()
+		if config.Mode != ProgressModeAuto {
+			defaultCfg.Mode = config.Mode
+		}
+		if config.Interval > 0 {
+			defaultCfg.Interval = config.Interval
+		}
+		if config.LogInterval > 0 {
+			defaultCfg.LogInterval = config.LogInterval
+		}
+		if config.Width > 0 {
+			defaultCfg.Width = config.Width
+		}
+	}
+
+	// mode
+	mode := defaultCfg.Mode
+	if mode == ProgressModeAuto {
+		switch {
+		case output == OutputModeJSON || w == nil:
+			mode = ProgressModeSilent
+		case isTerminal(w):
+			mode = ProgressModeTerminal
+		default:
+			mode = ProgressModeLog
+		}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p = &Progress{
+		w: w,
+		mode: mode,
+		width: defaultCfg.Width,
+		now: time.Now,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	// render loop
+	interval := defaultCfg.Interval
+	if mode == ProgressModeLog {
+		interval = defaultCfg.LogInterval
+	}
+	go p.loop(ctx, interval)
+	return
+}
+
+// Progress is the struct that renders a set of concurrent progress bars and spinners.
+type Progress struct {
+	// mu is the mutex that guards the bars and the rendering.
+	mu sync.Mutex
+	// w is the writer where the progress is rendered.
+	w io.Writer
+	// mode is the rendering mode.
+	mode ProgressMode
+	// width is the width of the bars.
+	width int
+	// now is the function that returns the current time.
+	now func() time.Time
+	// bars are the bars and spinners of the progress, in creation order.
+	bars []*Bar
+	// lines is the number of lines drawn on the terminal in the last render.
+	lines int
+	// frame is the current frame of the spinners.
+	frame int
+	// once guards the stop of the progress.
+	once sync.Once
+	// stop is the channel closed to stop the render loop.
+	stop chan struct{}
+	// done is the channel closed when the render loop has finished.
+	done chan struct{}
+}
+
+// Bar is the method that adds a progress bar of the given total.
+func (p *Progress) Bar(label string, total int64) (b *Bar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b = &Bar{p: p, label: label, total: total, start: p.now()}
+	p.bars = append(p.bars, b)
+	return
+}
+
+// Spinner is the method that adds a spinner, a bar without a known total.
+func (p *Progress) Spinner(label string) (b *Bar) {
+	b = p.Bar(label, 0)
+	return
+}
+
+// Stop is the method that stops the rendering, drawing the final state of the bars.
+// - it is safe to call it more than once
+func (p *Progress) Stop() {
+	p.once.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// loop is the method that renders the progress on each tick until it is stopped.
+func (p *Progress) loop(ctx context.Context, interval time.Duration) {
+	defer close(p.done)
+
+	if p.mode == ProgressModeSilent {
+		select {
+		case <-ctx.Done():
+		case <-p.stop:
+		}
+		return
+	}
+
+	// terminal: hide the cursor while rendering
+	if p.mode == ProgressModeTerminal {
+		fmt.Fprint(p.w, "\x1b[?25l")
+		defer fmt.Fprint(p.w, "\x1b[?25h")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.render(false)
+		case <-ctx.Done():
+			p.render(true)
+			return
+		case <-p.stop:
+			p.render(true)
+			return
+		}
+	}
+}
+
+// render is the method that renders the bars.
+func (p *Progress) render(final bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.frame++
+
+	var b strings.Builder
+	switch p.mode {
+	case ProgressModeTerminal:
+		// move to the first line of the last render and redraw
+		if p.lines > 0 {
+			fmt.Fprintf(&b, "\x1b[%dA", p.lines)
+		}
+		for _, bar := range p.bars {
+			fmt.Fprintf(&b, "\r\x1b[2K%s\n", bar.line(now, p.width, p.frame))
+		}
+		p.lines = len(p.bars)
+	case ProgressModeLog:
+		// finished bars are logged only once
+		for _, bar := range p.bars {
+			if bar.logged {
+				continue
+			}
+			if !final && bar.done {
+				bar.logged = true
+			}
+			fmt.Fprintf(&b, "%s\n", bar.logLine(now))
+		}
+	}
+
+	io.WriteString(p.w, b.String())
+}
+
+// Bar is the struct that represents a progress bar, or a spinner when the total is unknown.
+type Bar struct {
+	// p is the progress the bar belongs to.
+	p *Progress
+	// label is the label of the bar.
+	label string
+	// total is the total of the bar, 0 for a spinner.
+	total int64
+	// current is the current value of the bar.
+	current int64
+	// start is the time the bar was created.
+	start time.Time
+	// end is the time the bar was done.
+	end time.Time
+	// done is the flag that indicates if the bar is done.
+	done bool
+	// logged is the flag that indicates if the done state was already logged.
+	logged bool
+}
+
+// Add is the method that increments the bar.
+func (b *Bar) Add(n int64) {
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+
+	b.current += n
+	b.current = b.clamp(b.current)
+}
+
+// Set is the method that sets the current value of the bar.
+func (b *Bar) Set(n int64) {
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+
+	b.current = n
+	b.current = b.clamp(b.current)
+}
+
+// clamp is the method that keeps a value of the bar within [0, total], a spinner has no total.
+func (b *Bar) clamp(n int64) (r int64) {
+	r = n
+	if b.total > 0 {
+		r = min(max(n, 0), b.total)
+	}
+	return
+}
+
+// Done is the method that marks the bar as done.
+func (b *Bar) Done() {
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+
+	if b.done {
+		return
+	}
+	b.done = true
+	b.end = b.p.now()
+	if b.total > 0 {
+		b.current = b.total
+	}
+}
+
+// rate is the method that returns the throughput of the bar per second.
+func (b *Bar) rate(now time.Time) (r float64) {
+	if b.done {
+		now = b.end
+	}
+	elapsed := now.Sub(b.start).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	r = float64(b.clamp(b.current)) / elapsed
+	return
+}
+
+// eta is the method that returns the estimated time to finish the bar.
+// - ok is false when it can not be estimated yet
+func (b *Bar) eta(now time.Time) (d time.Duration, ok bool) {
+	r := b.rate(now)
+	if b.total <= 0 || r <= 0 {
+		return
+	}
+
+	d = time.Duration(float64(b.total-b.clamp(b.current)) / r * float64(time.Second)).Round(time.Second)
+	ok = true
+	return
+}
+
+// line is the method that returns the terminal line of the bar.
+func (b *Bar) line(now time.Time, width int, frame int) (l string) {
+	// spinner
+	if b.total <= 0 {
+		icon := spinnerFrames[frame%len(spinnerFrames)]
+		if b.done {
+			icon = "✓"
+		}
+		l = fmt.Sprintf("%s %s %d %.1f/s", icon, b.label, b.current, b.rate(now))
+		return
+	}
+
+	// bar
+	// - the current value is clamped, so the bar is never drawn out of its width
+	current := b.clamp(b.current)
+	ratio := float64(current) / float64(b.total)
+	filled := int(ratio * float64(width))
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+	l = fmt.Sprintf("%s [%s] %3.0f%% %d/%d %.1f/s", b.label, bar, ratio*100, current, b.total, b.rate(now))
+	if b.done {
+		l += " done"
+	} else if eta, ok := b.eta(now); ok {
+		l += " ETA " + eta.String()
+	}
+	return
+}
+
+// logLine is the method that returns the log line of the bar.
+func (b *Bar) logLine(now time.Time) (l string) {
+	if b.done {
+		end := b.end.Sub(b.start).Round(time.Millisecond)
+		l = fmt.Sprintf("%s: done (%d in %s)", b.label, b.current, end)
+		return
+	}
+
+	// spinner
+	if b.total <= 0 {
+		l = fmt.Sprintf("%s: %d (%.1f/s)", b.label, b.current, b.rate(now))
+		return
+	}
+
+	// bar
+	current := b.clamp(b.current)
+	ratio := float64(current) / float64(b.total)
+	l = fmt.Sprintf("%s: %.0f%% (%d/%d, %.1f/s", b.label, ratio*100, current, b.total, b.rate(now))
+	if eta, ok := b.eta(now); ok {
+		l += ", ETA " + eta.String()
+	}
+	l += ")"
+	return
+}
+
+// Progress is the method that returns a new progress rendered on the error writer of the input.
+// - it is silent for the json output and writes log lines if the error writer is not a terminal
+// - it stops when the context of the input is done
+func (i Input) Progress(cfg optional.Option[ConfigProgress]) (p *Progress) {
+	p = NewProgress(i.Context, i.ErrWriter, i.Output, cfg)
+	return
+}
@@ -0,0 +1,147 @@
+package gocli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBar_Line tests the rendering of the lines of a Bar.
+func TestBar_Line(t *testing.T) {
+	// start is the start time of the bars
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success - case 01: bar in progress", func(t *testing.T) {
+		// arrange
+		b := &Bar{label: "download", total: 100, current: 50, start: start}
+		now := start.Add(10 * time.Second)
+
+		// act
+		line := b.line(now, 10, 0)
+		logLine := b.logLine(now)
+
+		// assert
+		require.Equal(t, "download [=====>    ]  50% 50/100 5.0/s ETA 10s", line)
+		require.Equal(t, "download: 50% (50/100, 5.0/s, ETA 10s)", logLine)
+	})
+
+	t.Run("success - case 02: bar is done", func(t *testing.T) {
+		// arrange
+		b := &Bar{label: "download", total: 100, current: 100, start: start, end: start.Add(4 * time.Second), done: true}
+		now := start.Add(10 * time.Second)
+
+		// act
+		line := b.line(now, 10, 0)
+		logLine := b.logLine(now)
+
+		// assert
+		require.Equal(t, "download [==========] 100% 100/100 25.0/s done", line)
+		require.Equal(t, "download: done (100 in 4s)", logLine)
+	})
+
+	t.Run("success - case 04: current out of the total is clamped", func(t *testing.T) {
+		// arrange
+		p := &Progress{now: func() time.Time { return start }}
+		b := &Bar{p: p, label: "download", total: 100, start: start}
+		now := start.Add(10 * time.Second)
+
+		// act
+		b.Set(-5)
+		lineNegative := b.line(now, 10, 0)
+		b.current = 150
+		lineOver := b.line(now, 10, 0)
+		logLineOver := b.logLine(now)
+
+		// assert
+		require.Equal(t, "download [>         ]   0% 0/100 0.0/s", lineNegative)
+		require.Equal(t, "download [==========] 100% 100/100 10.0/s ETA 0s", lineOver)
+		require.Equal(t, "download: 100% (100/100, 10.0/s, ETA 0s)", logLineOver)
+	})
+
+	t.Run("success - case 03: spinner", func(t *testing.T) {
+		// arrange
+		b := &Bar{label: "indexing", current: 20, start: start}
+		now := start.Add(2 * time.Second)
+
+		// act
+		line := b.line(now, 10, 1)
+		logLine := b.logLine(now)
+
+		// assert
+		require.Equal(t, "⠙ indexing 20 10.0/s", line)
+		require.Equal(t, "indexing: 20 (10.0/s)", logLine)
+	})
+}
+
+// TestNewProgress tests the function NewProgress.
+func TestNewProgress(t *testing.T) {
+	t.Run("success - case 01: log mode when the writer is not a terminal", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		p := NewProgress(context.Background(), w, OutputModeText, optional.None[ConfigProgress]())
+
+		// act
+		b := p.Bar("download", 10)
+		b.Add(4)
+		b.Done()
+		p.Stop()
+
+		// assert
+		require.Equal(t, ProgressModeLog, p.mode)
+		require.True(t, strings.HasPrefix(w.String(), "download: done (10 in "))
+	})
+
+	t.Run("success - case 02: silent mode for the json output", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		p := NewProgress(context.Background(), w, OutputModeJSON, optional.None[ConfigProgress]())
+
+		// act
+		b := p.Bar("download", 10)
+		b.Done()
+		p.Stop()
+
+		// assert
+		require.Equal(t, ProgressModeSilent, p.mode)
+		require.Empty(t, w.String())
+	})
+
+	t.Run("success - case 03: terminal mode draws all the bars and restores the cursor", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		p := NewProgress(context.Background(), w, OutputModeText, optional.Some(ConfigProgress{Mode: ProgressModeTerminal, Width: 4}))
+
+		// act
+		p.Bar("a", 2).Done()
+		p.Spinner("b").Done()
+		p.Stop()
+
+		// assert
+		out := w.String()
+		require.True(t, strings.HasPrefix(out, "\x1b[?25l"))
+		require.True(t, strings.HasSuffix(out, "\x1b[?25h"))
+		require.Contains(t, out, "\r\x1b[2Ka [====] 100% 2/2")
+		require.Contains(t, out, "\r\x1b[2K✓ b 0")
+	})
+
+	t.Run("success - case 04: rendering stops when the context is cancelled", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		p := NewProgress(ctx, w, OutputModeText, optional.None[ConfigProgress]())
+		p.Bar("download", 10).Add(3)
+
+		// act
+		cancel()
+		<-p.done
+
+		// assert
+		require.True(t, strings.HasPrefix(w.String(), "download: 30% (3/10"))
+		p.Stop()
+	})
+}
@@ -0,0 +1,420 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPromptNotInteractive is the error that returns when a prompt is asked and stdin is not a terminal.
+	ErrPromptNotInteractive = errors.New("prompt requires an interactive terminal, stdin is not a terminal")
+	// ErrPromptInvalidAnswer is the error that returns when the answer given by a flag is invalid.
+	ErrPromptInvalidAnswer = errors.New("invalid answer")
+	// ErrPromptNoOptions is the error that returns when a Select or MultiSelect prompt has no options.
+	ErrPromptNoOptions = errors.New("prompt has no options")
+	// ErrPromptEcho is the error that returns when the echo of the terminal can not be turned off for a Password prompt.
+	ErrPromptEcho = errors.New("can not turn off the echo of the terminal")
+)
+
+// Prompt is the struct that represents a question asked to the user.
+type Prompt struct {
+	// Message is the question asked to the user.
+	Message string
+	// Flag is the name of the flag that answers the prompt.
+	// - if the flag is given, the prompt is skipped and its value is used as the answer
+	// - the default value of the flag does not answer the prompt
+	Flag string
+	// Default is the answer used when the user enters nothing.
+	// - for Confirm: "y" or "n"
+	// - for Select: one of the options
+	Default string
+	// Options are the choices of a Select or MultiSelect prompt.
+	Options []string
+	// Validate is the function that validates the answer of an Ask or Password prompt.
+	// - on error, the error is shown and the question is asked again
+	Validate func(answer string) (err error)
+}
+
+// Confirm is the method that asks a yes/no question, e.g. "delete 40 resources? [y/N]".
+func (i Input) Confirm(p Prompt) (ok bool, err error) {
+	// answer from flag
+	if value, has := i.flagAnswer(p); has {
+		ok, err = parseConfirm(value)
+		if err != nil {
+			err = fmt.Errorf("%w: flag %s: %q is not a yes/no value", ErrPromptInvalidAnswer, p.Flag, value)
+		}
+		return
+	}
+
+	// answer from user
+	def, _ := parseConfirm(p.Default)
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		var answer string
+		answer, err = i.ask(p, hint)
+		if err != nil {
+			return
+		}
+		if answer == "" {
+			ok = def
+			return
+		}
+
+		var perr error
+		ok, perr = parseConfirm(answer)
+		if perr == nil {
+			return
+		}
+		i.promptError(fmt.Errorf("please answer y or n"))
+	}
+}
+
+// Ask is the method that asks for a free-text answer.
+func (i Input) Ask(p Prompt) (answer string, err error) {
+	// answer from flag
+	if value, has := i.flagAnswer(p); has {
+		answer = value
+		if p.Validate != nil {
+			if verr := p.Validate(answer); verr != nil {
+				err = fmt.Errorf("%w: flag %s: %w", ErrPromptInvalidAnswer, p.Flag, verr)
+			}
+		}
+		return
+	}
+
+	// answer from user
+	var hint string
+	if p.Default != "" {
+		hint = "[" + p.Default + "]"
+	}
+	for {
+		answer, err = i.ask(p, hint)
+		if err != nil {
+			return
+		}
+		if answer == "" {
+			answer = p.Default
+		}
+
+		if p.Validate == nil {
+			return
+		}
+		verr := p.Validate(answer)
+		if verr == nil {
+			return
+		}
+		i.promptError(verr)
+	}
+}
+
+// Password is the method that asks for a secret answer, hiding it while it is typed.
+func (i Input) Password(p Prompt) (answer string, err error) {
+	// answer from flag
+	if value, has := i.flagAnswer(p); has {
+		answer = value
+		if p.Validate != nil {
+			if verr := p.Validate(answer); verr != nil {
+				err = fmt.Errorf("%w: flag %s: %w", ErrPromptInvalidAnswer, p.Flag, verr)
+			}
+		}
+		return
+	}
+
+	// answer from user
+	// - echo is turned off while typing, so the newline is written by the prompt
+	// - the answer is never read with the echo on, if it can not be turned off an error is returned
+	for {
+		if i.Interactive {
+			if eerr := setEcho(i.Reader, false); eerr != nil {
+				err = fmt.Errorf("%w: %q: %w", ErrPromptEcho, p.Message, eerr)
+				return
+			}
+		}
+		answer, err = i.ask(p, "")
+		_ = setEcho(i.Reader, true)
+		fmt.Fprintln(i.promptWriter())
+		if err != nil {
+			return
+		}
+
+		if p.Validate == nil {
+			return
+		}
+		verr := p.Validate(answer)
+		if verr == nil {
+			return
+		}
+		i.promptError(verr)
+	}
+}
+
+// Select is the method that asks to choose one of the options.
+func (i Input) Select(p Prompt) (choice string, err error) {
+	if len(p.Options) == 0 {
+		err = fmt.Errorf("%w: %q", ErrPromptNoOptions, p.Message)
+		return
+	}
+
+	// answer from flag
+	if value, has := i.flagAnswer(p); has {
+		if !contains(p.Options, value) {
+			err = fmt.Errorf("%w: flag %s: %q is not one of %s", ErrPromptInvalidAnswer, p.Flag, value, strings.Join(p.Options, ", "))
+			return
+		}
+		choice = value
+		return
+	}
+
+	// answer from user
+	i.writeOptions(p.Options)
+	hint := fmt.Sprintf("[1-%d]", len(p.Options))
+	if p.Default != "" {
+		hint = fmt.Sprintf("[1-%d, default %s]", len(p.Options), p.Default)
+	}
+	for {
+		var answer string
+		answer, err = i.ask(p, hint)
+		if err != nil {
+			return
+		}
+		if answer == "" && p.Default != "" {
+			choice = p.Default
+			return
+		}
+
+		index, perr := parseChoice(answer, len(p.Options))
+		if perr == nil {
+			choice = p.Options[index]
+			return
+		}
+		i.promptError(perr)
+	}
+}
+
+// MultiSelect is the method that asks to choose any number of the options.
+// - the user answers with comma separated numbers, e.g. "1,3"
+// - a flag answers with comma separated options, e.g. "--regions us,eu"
+func (i Input) MultiSelect(p Prompt) (choices []string, err error) {
+	if len(p.Options) == 0 {
+		err = fmt.Errorf("%w: %q", ErrPromptNoOptions, p.Message)
+		return
+	}
+
+	// answer from flag
+	if value, has := i.flagAnswer(p); has {
+		for _, v := range splitList(value) {
+			if !contains(p.Options, v) {
+				err = fmt.Errorf("%w: flag %s: %q is not one of %s", ErrPromptInvalidAnswer, p.Flag, v, strings.Join(p.Options, ", "))
+				return
+			}
+			choices = append(choices, v)
+		}
+		return
+	}
+
+	// answer from user
+	i.writeOptions(p.Options)
+	hint := "(comma separated, e.g. 1,2)"
+	for {
+		var answer string
+		answer, err = i.ask(p, hint)
+		if err != nil {
+			return
+		}
+
+		choices = nil
+		var perr error
+		for _, v := range splitList(answer) {
+			var index int
+			index, perr = parseChoice(v, len(p.Options))
+			if perr != nil {
+				break
+			}
+			choices = append(choices, p.Options[index])
+		}
+		if perr == nil {
+			return
+		}
+		i.promptError(perr)
+	}
+}
+
+// flagAnswer is the method that returns the answer of a prompt given by its flag.
+// - a flag that only has its default value is not an answer, the user is asked
+func (i Input) flagAnswer(p Prompt) (value string, ok bool) {
+	if p.Flag == "" || i.Flags == nil {
+		return
+	}
+	if i.given != nil && !i.given[p.Flag] {
+		return
+	}
+
+	v, ok := i.Flags[p.Flag]
+	if !ok {
+		return
+	}
+	value = fmt.Sprint(v)
+	return
+}
+
+// ask is the method that writes the question and reads a line from the reader.
+func (i Input) ask(p Prompt, hint string) (answer string, err error) {
+	if !i.Interactive || i.Reader == nil {
+		err = fmt.Errorf("%w: %q", ErrPromptNotInteractive, p.Message)
+		if p.Flag != "" {
+			err = fmt.Errorf("%w (answer it with the flag --%s)", err, p.Flag)
+		}
+		return
+	}
+
+	// question
+	w := i.promptWriter()
+	fmt.Fprintf(w, "%s %s ", i.Style.Color(ColorCyan, "?"), i.Style.Bold(p.Message))
+	if hint != "" {
+		fmt.Fprintf(w, "%s ", i.Style.Dim(hint))
+	}
+
+	// answer
+	answer, err = readLine(i.Reader)
+	answer = strings.TrimSpace(answer)
+	return
+}
+
+// writeOptions is the method that writes the numbered options of a select prompt.
+func (i Input) writeOptions(options []string) {
+	if !i.Interactive {
+		return
+	}
+
+	w := i.promptWriter()
+	for ix, option := range options {
+		fmt.Fprintf(w, "  %s %s\n", i.Style.Dim(strconv.Itoa(ix+1)+")"), option)
+	}
+}
+
+// promptError is the method that writes the error of an answer before asking again.
+func (i Input) promptError(err error) {
+	fmt.Fprintf(i.promptWriter(), "%s %s\n", i.Style.Color(ColorRed, "!"), err)
+}
+
+// promptWriter is the method that returns the writer of the prompts.
+// - prompts are written to the error writer, so they never mix with the output of the command
+func (i Input) promptWriter() (w io.Writer) {
+	w = i.ErrWriter
+	if w == nil {
+		w = io.Discard
+	}
+	return
+}
+
+// readLine is the function that reads a line from the reader, without the line break.
+// - it reads byte by byte, so no input is buffered away from the next prompt
+func readLine(r io.Reader) (line string, err error) {
+	var b strings.Builder
+	buf := make([]byte, 1)
+	for {
+		var n int
+		n, err = r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				err = nil
+				break
+			}
+			b.WriteByte(buf[0])
+		}
+		if err != nil {
+			// the last line may not end with a line break
+			if errors.Is(err, io.EOF) && b.Len() > 0 {
+				err = nil
+				break
+			}
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return
+		}
+	}
+
+	line = strings.TrimSuffix(b.String(), "\r")
+	return
+}
+
+// setEcho is the function that turns on or off the echo of a terminal reader.
+// - if the reader is not a terminal there is no echo, so nothing is done
+// - if the reader is a terminal, an error is returned when stty is not available or fails,
+// or when the terminal is not a file of the process, e.g. the one of a daemon client
+func setEcho(r io.Reader, on bool) (err error) {
+	if !isTerminal(r) {
+		return
+	}
+	f, ok := r.(*os.File)
+	if !ok {
+		err = errors.New("the terminal is not a file of the process")
+		return
+	}
+
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = f
+	err = cmd.Run()
+	return
+}
+
+// parseConfirm is the function that parses a yes/no answer.
+func parseConfirm(value string) (ok bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "y", "yes":
+		ok = true
+	case "n", "no":
+		ok = false
+	default:
+		ok, err = strconv.ParseBool(value)
+	}
+	return
+}
+
+// parseChoice is the function that parses the number of an option into its index.
+func parseChoice(value string, size int) (index int, err error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 1 || n > size {
+		err = fmt.Errorf("please enter a number between 1 and %d", size)
+		return
+	}
+
+	index = n - 1
+	return
+}
+
+// splitList is the function that splits a comma separated list, ignoring empty items.
+func splitList(value string) (items []string) {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+// contains is the function that checks if a list contains a value.
+func contains(list []string, value string) (ok bool) {
+	for _, item := range list {
+		if item == value {
+			ok = true
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,292 @@
+package gocli_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// newPromptInput is the function that returns an interactive input reading the given answers.
+func newPromptInput(answers string, w *bytes.Buffer) (i gocli.Input) {
+	i = gocli.Input{
+		Reader:      strings.NewReader(answers),
+		ErrWriter:   w,
+		Interactive: true,
+	}
+	return
+}
+
+// TestInput_Confirm is the test for the method Confirm.
+func TestInput_Confirm(t *testing.T) {
+	t.Run("success - case 01: user answers yes", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("y\n", w)
+
+		// act
+		ok, err := i.Confirm(gocli.Prompt{Message: "delete 40 resources?"})
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "? delete 40 resources? [y/N] ", w.String())
+	})
+
+	t.Run("success - case 02: user answers nothing - default is used", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("\n", w)
+
+		// act
+		ok, err := i.Confirm(gocli.Prompt{Message: "continue?", Default: "y"})
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "? continue? [Y/n] ", w.String())
+	})
+
+	t.Run("success - case 03: invalid answer is asked again", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("maybe\nno\n", w)
+
+		// act
+		ok, err := i.Confirm(gocli.Prompt{Message: "continue?"})
+
+		// assert
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Equal(t, "? continue? [y/N] ! please answer y or n\n? continue? [y/N] ", w.String())
+	})
+
+	t.Run("success - case 04: flag has a value - prompt is skipped", func(t *testing.T) {
+		// arrange
+		i := gocli.Input{Flags: map[string]any{"yes": "true"}}
+
+		// act
+		ok, err := i.Confirm(gocli.Prompt{Message: "continue?", Flag: "yes"})
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("failure - case 01: stdin is not a terminal", func(t *testing.T) {
+		// arrange
+		i := gocli.Input{Reader: strings.NewReader("y\n")}
+
+		// act
+		_, err := i.Confirm(gocli.Prompt{Message: "continue?", Flag: "yes"})
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrPromptNotInteractive)
+		require.EqualError(t, err, `prompt requires an interactive terminal, stdin is not a terminal: "continue?" (answer it with the flag --yes)`)
+	})
+}
+
+// TestCLI_Run_Prompt is the test for the prompts of the handlers run by the method Run.
+func TestCLI_Run_Prompt(t *testing.T) {
+	// newCLI is the function that returns a cli whose handler confirms with the flag yes, with a default
+	newCLI := func(ok *bool) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name:  "cleanup",
+			Flags: gocli.Flags{{Name: "yes", Type: gocli.FlagTypeBool, Default: "false"}},
+			Handler: func(i gocli.Input) (err error) {
+				*ok, err = i.Confirm(gocli.Prompt{Message: "delete 40 resources?", Flag: "yes"})
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Reader = strings.NewReader("y\n")
+		cli.ErrWriter = &bytes.Buffer{}
+		return
+	}
+
+	t.Run("success - case 01: flag given - prompt is skipped", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "cleanup", "--yes", "true"}
+		var ok bool
+		cli := newCLI(&ok)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("failure - case 01: flag not given - its default does not answer the prompt", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "cleanup"}
+		var ok bool
+		cli := newCLI(&ok)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrPromptNotInteractive)
+		require.False(t, ok)
+	})
+}
+
+// TestInput_Ask is the test for the method Ask.
+func TestInput_Ask(t *testing.T) {
+	// notEmpty is the validation used in the tests
+	notEmpty := func(answer string) (err error) {
+		if answer == "" {
+			err = errors.New("name can not be empty")
+		}
+		return
+	}
+
+	t.Run("success - case 01: answer is validated and asked again", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("\n  gopher  \n", w)
+
+		// act
+		answer, err := i.Ask(gocli.Prompt{Message: "name:", Validate: notEmpty})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "gopher", answer)
+		require.Equal(t, "? name: ! name can not be empty\n? name: ", w.String())
+	})
+
+	t.Run("success - case 02: answer is the last line without line break", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("gopher", w)
+
+		// act
+		answer, err := i.Ask(gocli.Prompt{Message: "name:"})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "gopher", answer)
+	})
+
+	t.Run("failure - case 01: flag value is invalid", func(t *testing.T) {
+		// arrange
+		i := gocli.Input{Flags: map[string]any{"name": ""}}
+
+		// act
+		_, err := i.Ask(gocli.Prompt{Message: "name:", Flag: "name", Validate: notEmpty})
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrPromptInvalidAnswer)
+	})
+}
+
+// TestInput_Password is the test for the method Password.
+func TestInput_Password(t *testing.T) {
+	t.Run("success - case 01: password is read", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("s3cr3t\n", w)
+
+		// act
+		answer, err := i.Password(gocli.Prompt{Message: "password:"})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", answer)
+		require.NotContains(t, w.String(), "s3cr3t")
+	})
+}
+
+// TestInput_Select is the test for the method Select.
+func TestInput_Select(t *testing.T) {
+	t.Run("success - case 01: user chooses an option", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("3\n2\n", w)
+
+		// act
+		choice, err := i.Select(gocli.Prompt{Message: "env:", Options: []string{"dev", "prod"}})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "prod", choice)
+		require.Equal(t, "  1) dev\n  2) prod\n"+
+			"? env: [1-2] ! please enter a number between 1 and 2\n"+
+			"? env: [1-2] ", w.String())
+	})
+
+	t.Run("failure - case 01: flag value is not an option", func(t *testing.T) {
+		// arrange
+		i := gocli.Input{Flags: map[string]any{"env": "stage"}}
+
+		// act
+		_, err := i.Select(gocli.Prompt{Message: "env:", Flag: "env", Options: []string{"dev", "prod"}})
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrPromptInvalidAnswer)
+	})
+
+	t.Run("failure - case 02: no options", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("1\n", w)
+
+		// act
+		_, err := i.Select(gocli.Prompt{Message: "env:"})
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrPromptNoOptions)
+		require.Empty(t, w.String())
+	})
+}
+
+// TestInput_MultiSelect is the test for the method MultiSelect.
+func TestInput_MultiSelect(t *testing.T) {
+	t.Run("success - case 01: user chooses some options", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("1, 3\n", w)
+
+		// act
+		choices, err := i.MultiSelect(gocli.Prompt{Message: "regions:", Options: []string{"us", "eu", "ap"}})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"us", "ap"}, choices)
+	})
+
+	t.Run("success - case 02: flag has a value - prompt is skipped", func(t *testing.T) {
+		// arrange
+		i := gocli.Input{Flags: map[string]any{"regions": "eu,ap"}}
+
+		// act
+		choices, err := i.MultiSelect(gocli.Prompt{Message: "regions:", Flag: "regions", Options: []string{"us", "eu", "ap"}})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"eu", "ap"}, choices)
+	})
+	t.Run("failure - case 01: no options", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+		i := newPromptInput("1\n", w)
+
+		// act
+		_, err := i.MultiSelect(gocli.Prompt{Message: "regions:"})
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrPromptNoOptions)
+		require.Empty(t, w.String())
+	})
+}
@@ -0,0 +1,155 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrRegistryConflict is the error that returns when registered commands conflict between them or with the tree.
+	ErrRegistryConflict = errors.New("registry conflict")
+	// ErrRegistryInvalidPath is the error that returns when a command is registered with an empty path.
+	ErrRegistryInvalidPath = errors.New("registry invalid path")
+	// ErrRegistryNotSupported is the error that returns when the commander can not mount a registry.
+	ErrRegistryNotSupported = errors.New("registry not supported by commander")
+)
+
+// DefaultRegistry is the registry used by Register.
+var DefaultRegistry = NewRegistry()
+
+// Register is the function that registers a command in the DefaultRegistry.
+// - it is meant to be called from init(), e.g. gocli.Register("db migrate", cmd)
+func Register(path string, cmd Command) {
+	DefaultRegistry.Register(path, cmd)
+}
+
+// NewRegistry is the function that returns a new registry.
+func NewRegistry() (r *Registry) {
+	r = &Registry{}
+	return
+}
+
+// Registry is the struct that collects the commands contributed by several packages,
+// to mount them later in a command manager tree.
+type Registry struct {
+	// mu is the mutex that guards the entries.
+	mu sync.Mutex
+	// entries are the commands registered, in registration order.
+	entries []registryEntry
+}
+
+// registryEntry is the struct that represents a command registered with its path.
+type registryEntry struct {
+	// path is the chain of groups followed by the name of the command.
+	path []string
+	// cmd is the command.
+	cmd Command
+}
+
+// Register is the method that registers a command at a path.
+// - the path is the chain of groups followed by the name of the command, e.g. "db migrate"
+// - the name of the command is taken from the path
+func (r *Registry) Register(path string, cmd Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fields := strings.Fields(path)
+	if len(fields) > 0 {
+		cmd.Name = fields[len(fields)-1]
+	}
+	r.entries = append(r.entries, registryEntry{path: fields, cmd: cmd})
+}
+
+// Mount is the method that mounts the commands of the registry in the command manager.
+// - the groups of the paths are created on demand, existing groups are reused
+// - conflicting commands are not mounted, all the conflicts are reported in a single error sorted by path
+func (c *CommanderManager) Mount(r *Registry) (err error) {
+	r.mu.Lock()
+	entries := append([]registryEntry{}, r.entries...)
+	r.mu.Unlock()
+
+	// deterministic order: by path, then by registration order
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.Join(entries[i].path, " ") < strings.Join(entries[j].path, " ")
+	})
+
+	var conflicts []string
+	for _, entry := range entries {
+		if conflict := c.mountEntry(entry); conflict != "" {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	if len(conflicts) == 0 {
+		return
+	}
+
+	sort.Strings(conflicts)
+	err = fmt.Errorf("%w: %s", ErrRegistryConflict, strings.Join(conflicts, "; "))
+	return
+}
+
+// mountEntry is the method that mounts a registered command, returning the conflict if any.
+func (c *CommanderManager) mountEntry(entry registryEntry) (conflict string) {
+	path := strings.Join(entry.path, " ")
+	if len(entry.path) == 0 {
+		conflict = fmt.Sprintf("%q: %s", path, ErrRegistryInvalidPath)
+		return
+	}
+
+	// groups, created on demand
+	current := c
+	chain, name := entry.path[:len(entry.path)-1], entry.path[len(entry.path)-1]
+	for i, group := range chain {
+		if _, err := current.Cmds.FindCommand(group); err == nil {
+			conflict = fmt.Sprintf("%q: %q is already a command", path, strings.Join(chain[:i+1], " "))
+			return
+		}
+
+		next := current.findGroup(group)
+		if next == nil {
+			next = current.Group(group, "").(*CommanderManager)
+		}
+		current = next
+	}
+
+	// command
+	// - its aliases can not be the name or an alias of another command, or the name of a group
+	if _, err := current.Cmds.FindCommand(name); err == nil {
+		conflict = fmt.Sprintf("%q: command already exists", path)
+		return
+	}
+	if current.findGroup(name) != nil {
+		conflict = fmt.Sprintf("%q: %q is already a group", path, path)
+		return
+	}
+	for _, alias := range entry.cmd.Aliases {
+		aliasPath := strings.Join(append(append([]string{}, chain...), alias), " ")
+		if _, err := current.Cmds.FindCommand(alias); err == nil {
+			conflict = fmt.Sprintf("%q: alias %q is already a command", path, aliasPath)
+			return
+		}
+		if current.findGroup(alias) != nil {
+			conflict = fmt.Sprintf("%q: alias %q is already a group", path, aliasPath)
+			return
+		}
+	}
+
+	current.AddCommand(entry.cmd)
+	return
+}
+
+// Mount is the method that mounts the commands of a registry in the commander of the CLI,
+// e.g. cli.Mount(gocli.DefaultRegistry) to mount the commands registered from init().
+func (c CLI) Mount(r *Registry) (err error) {
+	cm, ok := c.Commander.(*CommanderManager)
+	if !ok {
+		err = ErrRegistryNotSupported
+		return
+	}
+
+	err = cm.Mount(r)
+	return
+}
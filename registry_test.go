@@ -0,0 +1,135 @@
+package gocli_test
+
+import (
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommanderManager_Mount is the test for the method Mount.
+func TestCommanderManager_Mount(t *testing.T) {
+	t.Run("success - case 01: commands are mounted with their groups", func(t *testing.T) {
+		// arrange
+		// - registry
+		r := gocli.NewRegistry()
+		r.Register("db migrate", gocli.Command{Description: "runs the migrations"})
+		r.Register("db seed", gocli.Command{Description: "seeds the database"})
+		r.Register("version", gocli.Command{Description: "prints the version"})
+		// - command manager: db already exists
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.Group("db", "database commands")
+
+		// act
+		err := cmg.Mount(r)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, cmg.CommandManagers, 1)
+		require.Equal(t, "database commands", cmg.CommandManagers[0].Description)
+		p, err := cmg.FindCommandPath("migrate", "db")
+		require.NoError(t, err)
+		require.Equal(t, "runs the migrations", p.Command.Description)
+		_, err = cmg.FindCommandPath("version")
+		require.NoError(t, err)
+	})
+
+	t.Run("success - case 02: nested groups are created on demand", func(t *testing.T) {
+		// arrange
+		// - registry
+		r := gocli.NewRegistry()
+		r.Register("cloud db backup", gocli.Command{})
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+
+		// act
+		err := cmg.Mount(r)
+
+		// assert
+		require.NoError(t, err)
+		p, err := cmg.FindCommandPath("backup", "cloud", "db")
+		require.NoError(t, err)
+		require.Equal(t, "backup", p.Command.Name)
+	})
+
+	t.Run("failure - case 01: conflicts are reported sorted by path", func(t *testing.T) {
+		// arrange
+		// - registry
+		r := gocli.NewRegistry()
+		r.Register("version", gocli.Command{Description: "second"})
+		r.Register("db", gocli.Command{})
+		r.Register("db migrate", gocli.Command{Description: "first"})
+		r.Register("db migrate", gocli.Command{Description: "second"})
+		r.Register("", gocli.Command{})
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.AddCommand(gocli.Command{Name: "version", Description: "first"})
+
+		// act
+		err := cmg.Mount(r)
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrRegistryConflict)
+		require.EqualError(t, err, `registry conflict: "": registry invalid path; `+
+			`"db migrate": "db" is already a command; `+
+			`"db migrate": "db" is already a command; `+
+			`"version": command already exists`)
+	})
+
+	t.Run("failure - case 02: command name is already a group", func(t *testing.T) {
+		// arrange
+		// - registry
+		r := gocli.NewRegistry()
+		r.Register("db", gocli.Command{})
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.Group("db", "database commands")
+
+		// act
+		err := cmg.Mount(r)
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrRegistryConflict)
+		require.EqualError(t, err, `registry conflict: "db": "db" is already a group`)
+	})
+
+	t.Run("failure - case 03: alias is already a command or a group", func(t *testing.T) {
+		// arrange
+		// - registry
+		r := gocli.NewRegistry()
+		r.Register("db migrate", gocli.Command{Aliases: []string{"status"}})
+		r.Register("deploy", gocli.Command{Aliases: []string{"db"}})
+		r.Register("ls", gocli.Command{Aliases: []string{"list"}})
+		r.Register("list", gocli.Command{})
+		// - command manager
+		cmg := gocli.NewCommanderManager("app", "app description")
+		cmg.Group("db", "database commands").AddCommand(gocli.Command{Name: "status"})
+
+		// act
+		err := cmg.Mount(r)
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrRegistryConflict)
+		require.EqualError(t, err, `registry conflict: "db migrate": alias "db status" is already a command; `+
+			`"deploy": alias "db" is already a group; `+
+			`"ls": alias "list" is already a command`)
+	})
+}
+
+// TestCLI_Mount is the test for the method Mount of the CLI.
+func TestCLI_Mount(t *testing.T) {
+	t.Run("failure - case 01: commander is not a command manager", func(t *testing.T) {
+		// arrange
+		cli := gocli.NewCLI(gocli.NewParserMock(), gocli.NewCommanderMock())
+
+		// act
+		err := cli.Mount(gocli.NewRegistry())
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrRegistryNotSupported)
+	})
+}
@@ -0,0 +1,147 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrFlagSecretConflict is the error that returns when a secret flag is given both inline and from a file.
+	ErrFlagSecretConflict = errors.New("secret flag given more than once")
+	// ErrFlagSecretRead is the error that returns when the value of a secret flag can not be read.
+	ErrFlagSecretRead = errors.New("secret flag could not be read")
+	// ErrFlagSecretRemote is the error that returns when a client of ServeHandler or ServeMCP gives a secret flag from a file.
+	ErrFlagSecretRemote = errors.New("secret flag can only be read from a file on the command line")
+)
+
+// Redacted is the text shown in place of the value of a secret flag.
+const Redacted = "***"
+
+// readSecretFlags is the function that reads the values of the secret flags from their files or stdin.
+// - --<name>-file <path> reads the value from the file
+// - --<name>-file /dev/stdin (or -) reads the value from r, the stdin of the input
+// - a trailing newline of the value is removed
+// - remote is true if the flags were given by a client, e.g. of ServeHandler: the files would be read on the
+// server for the client, e.g. {"token-file": "/etc/shadow"}, so --<name>-file is rejected with ErrFlagSecretRemote
+// - it returns the flags with the values read, and the names of the secret flags given inline
+func readSecretFlags(specs Flags, flags map[string]any, r io.Reader, remote bool) (result map[string]any, inline []string, err error) {
+	result = flags
+	copied := false
+	for _, f := range specs {
+		if !f.Secret {
+			continue
+		}
+		_, isInline := flags[f.Name]
+		path, isFile := flags[f.Name+"-file"]
+		if !isInline && !isFile {
+			continue
+		}
+
+		// copy, the flags of the input are not modified
+		if !copied {
+			result = make(map[string]any, len(flags))
+			for k, v := range flags {
+				result[k] = v
+			}
+			copied = true
+		}
+
+		var b []byte
+		switch {
+		case isInline && isFile:
+			err = fmt.Errorf("%w: --%s and --%s-file", ErrFlagSecretConflict, f.Name, f.Name)
+			return
+		case isFile && remote:
+			err = fmt.Errorf("%w: --%s-file", ErrFlagSecretRemote, f.Name)
+			return
+		case isFile && (path == "-" || path == "/dev/stdin"):
+			if r == nil {
+				err = fmt.Errorf("%w: --%s-file: no stdin", ErrFlagSecretRead, f.Name)
+				return
+			}
+			b, err = io.ReadAll(r)
+			if err != nil {
+				err = fmt.Errorf("%w: --%s-file: %w", ErrFlagSecretRead, f.Name, err)
+				return
+			}
+			delete(result, f.Name+"-file")
+		case isFile:
+			b, err = os.ReadFile(fmt.Sprint(path))
+			if err != nil {
+				err = fmt.Errorf("%w: --%s-file: %w", ErrFlagSecretRead, f.Name, err)
+				return
+			}
+			delete(result, f.Name+"-file")
+		default:
+			inline = append(inline, f.Name)
+			continue
+		}
+		result[f.Name] = strings.TrimRight(string(b), "\r\n")
+	}
+	return
+}
+
+// secretFlagNames is the function that returns the names of the secret flags of all the commands of a commander.
+// - it is used to redact the args before the command is known, e.g. in logs
+// - the persistent flags of the groups are included, at every level
+func secretFlagNames(c Commander) (names map[string]bool) {
+	names = make(map[string]bool)
+	cm, ok := c.(*CommanderManager)
+	if !ok {
+		return
+	}
+
+	var walk func(g *CommanderManager)
+	walk = func(g *CommanderManager) {
+		for _, f := range g.Flags {
+			if f.Secret {
+				names[f.Name] = true
+			}
+		}
+		for _, cmd := range g.Cmds {
+			for _, f := range cmd.Flags {
+				if f.Secret {
+					names[f.Name] = true
+				}
+			}
+		}
+		for _, sub := range g.CommandManagers {
+			walk(sub)
+		}
+	}
+	walk(cm)
+	return
+}
+
+// redactArgs is the function that returns the args with the values of the secret flags redacted.
+// - both forms are redacted: `--name value` and `--name=value`
+func redactArgs(args []string, secrets map[string]bool) (r []string) {
+	r = make([]string, len(args))
+	copy(r, args)
+	if len(secrets) == 0 {
+		return
+	}
+
+	for i := 0; i < len(r); i++ {
+		if !strings.HasPrefix(r[i], "-") {
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(r[i], "-"), "=")
+		if !secrets[name] {
+			continue
+		}
+
+		if hasValue {
+			r[i] = r[i][:strings.Index(r[i], "=")+1] + Redacted
+			continue
+		}
+		if i+1 < len(r) {
+			r[i+1] = Redacted
+			i++
+		}
+	}
+	return
+}
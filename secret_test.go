@@ -0,0 +1,241 @@
+package gocli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_Run_Secret is the test for the secret flags of the method Run.
+func TestCLI_Run_Secret(t *testing.T) {
+	// newCommander is the function that returns the commander used in the tests, storing the flags of the handler
+	newCommander := func(flags *map[string]any) (cm *gocli.CommanderManager) {
+		cm = gocli.NewCommanderManager("app", "app description")
+		cm.AddCommand(gocli.Command{
+			Name: "login",
+			Flags: gocli.Flags{
+				{Name: "token", Description: "api token", Default: "dev-token", Secret: true},
+				{Name: "pin", Type: gocli.FlagTypeInt, Secret: true},
+			},
+			Handler: func(i gocli.Input) (err error) {
+				*flags = i.Flags
+				return
+			},
+		})
+		vault := cm.Group("vault", "vault commands")
+		vault.(gocli.GroupConfigurer).AddFlag(gocli.Flag{Name: "key", Description: "vault key", Secret: true})
+		vault.AddCommand(gocli.Command{
+			Name: "read",
+			Handler: func(i gocli.Input) (err error) {
+				*flags = i.Flags
+				return
+			},
+		})
+		return
+	}
+
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func(flags *map[string]any, stdin string, stderr *bytes.Buffer) (cli gocli.CLI) {
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), newCommander(flags))
+		cli.Reader = strings.NewReader(stdin)
+		cli.Writer = &bytes.Buffer{}
+		cli.ErrWriter = stderr
+		return
+	}
+
+	t.Run("success - case 01: value is read from a file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "token.txt")
+		require.NoError(t, os.WriteFile(path, []byte("s3cr3t\n"), 0o600))
+		os.Args = []string{"app.exe", "login", "--token-file", path}
+		var flags map[string]any
+		var stderr bytes.Buffer
+		cli := newCLI(&flags, "", &stderr)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"token": "s3cr3t"}, flags)
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("success - case 02: value is read from stdin", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "login", "--token-file", "/dev/stdin"}
+		var flags map[string]any
+		var stderr bytes.Buffer
+		cli := newCLI(&flags, "s3cr3t\n", &stderr)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"token": "s3cr3t"}, flags)
+	})
+
+	t.Run("success - case 03: inline value is warned about and redacted in logs", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "-vv", "login", "--token", "s3cr3t"}
+		var flags map[string]any
+		var stderr bytes.Buffer
+		cli := newCLI(&flags, "", &stderr)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"token": "s3cr3t"}, flags)
+		require.Contains(t, stderr.String(), `warning: secret flag "--token" given inline, it may be recorded in the shell history: use --token-file instead`)
+		require.Contains(t, stderr.String(), `args="[login --token ***]"`)
+		require.NotContains(t, stderr.String(), "s3cr3t")
+	})
+
+	t.Run("success - case 04: default is redacted in help and manifest", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cm := newCommander(&flags)
+		var help bytes.Buffer
+
+		// act
+		err := cm.WriteHelp(&help, gocli.NewStyler(&help, gocli.ColorModeNever), "login")
+		manifest, merr := json.Marshal(gocli.NewManifest(cm))
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, merr)
+		require.Contains(t, help.String(), "api token (default: ***) (secret, or --token-file)")
+		require.NotContains(t, help.String(), "dev-token")
+		require.Contains(t, string(manifest), `"default":"***"`)
+		require.Contains(t, string(manifest), `"secret":true`)
+		require.Contains(t, string(manifest), `"writeOnly":true`)
+		require.NotContains(t, string(manifest), "dev-token")
+	})
+
+	t.Run("success - case 05: value is read from stdin with -", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "login", "--token-file", "-"}
+		var flags map[string]any
+		var stderr bytes.Buffer
+		cli := newCLI(&flags, "s3cr3t\n", &stderr)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"token": "s3cr3t"}, flags)
+	})
+
+	t.Run("success - case 06: persistent secret flag is redacted in logs", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "-vv", "vault", "--key", "k3y", "read"}
+		var flags map[string]any
+		var stderr bytes.Buffer
+		cli := newCLI(&flags, "", &stderr)
+		cli.ExpandEnv = true
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"key": "k3y"}, flags)
+		require.Contains(t, stderr.String(), `argv="[-vv vault --key *** read]"`)
+		require.Contains(t, stderr.String(), `args="[vault --key *** read]"`)
+		require.NotContains(t, stderr.String(), "k3y")
+	})
+
+	t.Run("failure - case 01: value given inline and from a file", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "login", "--token", "s3cr3t", "--token-file", "token.txt"}
+		var flags map[string]any
+		cli := newCLI(&flags, "", &bytes.Buffer{})
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagSecretConflict)
+		require.EqualError(t, err, "secret flag given more than once: --token and --token-file")
+	})
+
+	t.Run("failure - case 02: file can not be read", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "login", "--token-file", filepath.Join(t.TempDir(), "missing.txt")}
+		var flags map[string]any
+		cli := newCLI(&flags, "", &bytes.Buffer{})
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagSecretRead)
+	})
+
+	t.Run("failure - case 03: invalid value is redacted in the error", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "login", "--pin", "abcd"}
+		var flags map[string]any
+		cli := newCLI(&flags, "", &bytes.Buffer{})
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrFlagInvalidType)
+		require.EqualError(t, err, `login: flag has an invalid type: --pin: expected int, got "***"`)
+	})
+	t.Run("failure - case 04: file is not read for a serve client", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "token.txt")
+		require.NoError(t, os.WriteFile(path, []byte("s3cr3t\n"), 0o600))
+		var flags map[string]any
+		srv := httptest.NewServer(newCLI(&flags, "", &bytes.Buffer{}).ServeHandler())
+		defer srv.Close()
+		body, err := json.Marshal(map[string]any{"flags": map[string]any{"token-file": path}})
+		require.NoError(t, err)
+
+		// act
+		r, err := http.Post(srv.URL+"/login", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer r.Body.Close()
+		var resp gocli.ServeResponse
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&resp))
+
+		// assert
+		require.Equal(t, "secret flag can only be read from a file on the command line: --token-file", resp.Error)
+		require.NotContains(t, resp.Output+resp.Stderr, "s3cr3t")
+		require.Nil(t, flags)
+	})
+
+	t.Run("failure - case 05: stdin is not read for an MCP client", func(t *testing.T) {
+		// arrange
+		var flags map[string]any
+		cli := newCLI(&flags, "", &bytes.Buffer{})
+		request := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "login", "arguments": {"token-file": "-"}}}`
+		var out bytes.Buffer
+
+		// act
+		err := cli.ServeMCP(context.Background(), strings.NewReader(request), &out)
+
+		// assert
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `"isError":true`)
+		require.Contains(t, out.String(), "secret flag can only be read from a file on the command line: --token-file")
+		require.Nil(t, flags)
+	})
+}
@@ -0,0 +1,242 @@
+package gocli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Limits of the HTTP server of Serve.
+const (
+	// serveMaxBodyBytes is the maximum size of the body of a request.
+	serveMaxBodyBytes = 1 << 20
+	// serveReadTimeout is the time to read a request, including its body.
+	serveReadTimeout = 10 * time.Second
+	// serveWriteTimeout is the time to run a command and write its response.
+	serveWriteTimeout = 5 * time.Minute
+	// serveIdleTimeout is the time a keep-alive connection waits for the next request.
+	serveIdleTimeout = 2 * time.Minute
+)
+
+// ServeRequest is the struct that represents the JSON body of a request to a command.
+type ServeRequest struct {
+	// Flags are the flags of the command, e.g. {"env": "prod"}.
+	Flags map[string]any `json:"flags"`
+	// Options are the options of the command, e.g. ["O1"].
+	Options []string `json:"options"`
+}
+
+// ServeResponse is the struct that represents the JSON body of the response of a command.
+type ServeResponse struct {
+	// Output is what the command wrote to its writer.
+	Output string `json:"output"`
+	// Stderr is what the command wrote to its error writer, e.g. warnings.
+	Stderr string `json:"stderr,omitempty"`
+	// Error is the error returned by the command, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+	// ExitCode is the exit code the command would have ended the process with.
+	ExitCode int `json:"exit_code"`
+}
+
+// Serve is the method that exposes the commands of the CLI as local HTTP/JSON endpoints.
+// - see ServeHandler
+// - the server has read, write and idle timeouts, use ServeHandler with an http.Server for other limits
+func (c CLI) Serve(addr string) (err error) {
+	srv := &http.Server{
+		Addr: addr,
+		Handler: c.ServeHandler(),
+		ReadHeaderTimeout: serveReadTimeout,
+		ReadTimeout: serveReadTimeout,
+		WriteTimeout: serveWriteTimeout,
+		IdleTimeout: serveIdleTimeout,
+	}
+	err = srv.ListenAndServe()
+	return
+}
+
+// ServeHandler is the method that returns the HTTP handler exposing the commands of the CLI.
+// - the path of the request is the chain of the command, e.g. POST /deploy/status runs `app deploy status`
+// - the body is a ServeRequest of at most 1 MiB, the response is a ServeResponse
+// - the commands run unmodified through the same Commander, with the json output mode and no terminal
+// - hidden commands, commands of hidden groups, commands with raw args (e.g. plugins) and local commands are not exposed
+// - a handler that does not return within the grace period after its request is cancelled or timed out is left running,
+// its goroutine is only freed when it returns
+//
+// The handler is meant for local tools, it has no authentication. Any web page open in a browser of the machine can
+// send requests to it, so:
+// - the Content-Type must be application/json, which a page can only send after a CORS preflight that is never allowed,
+// so cross-site requests (CSRF) never run a command
+// - the Host header must be localhost, a loopback address or one of CLI.ServeHosts, so a page of a domain that
+// resolves to the machine (DNS rebinding) is rejected
+// - the secret flags are never read from files or stdin, e.g. {"token-file": "/etc/shadow"}, see readSecretFlags
+func (c CLI) ServeHandler() (h http.Handler) {
+	h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// host
+		if !c.serveHostAllowed(r.Host) {
+			writeServeResponse(w, http.StatusForbidden, ServeResponse{Error: "host not allowed", ExitCode: 1})
+			return
+		}
+
+		// method
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeServeResponse(w, http.StatusMethodNotAllowed, ServeResponse{Error: "method not allowed", ExitCode: 1})
+			return
+		}
+
+		// content type
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			writeServeResponse(w, http.StatusUnsupportedMediaType, ServeResponse{Error: "content type must be application/json", ExitCode: 1})
+			return
+		}
+
+		// command
+		chain := strings.Fields(strings.ReplaceAll(strings.Trim(r.URL.Path, "/"), "/", " "))
+		if len(chain) == 0 || !c.served(chain) {
+			writeServeResponse(w, http.StatusNotFound, ServeResponse{Error: ErrCommandHandlerNotFound.Error(), ExitCode: 1})
+			return
+		}
+
+		// body
+		var req ServeRequest
+		r.Body = http.MaxBytesReader(w, r.Body, serveMaxBodyBytes)
+		if r.ContentLength != 0 {
+			// - numbers are kept as they are written, e.g. 1000000 is not turned into 1e+06
+			dec := json.NewDecoder(r.Body)
+			dec.UseNumber()
+			if err := dec.Decode(&req); err != nil {
+				writeServeResponse(w, http.StatusBadRequest, ServeResponse{Error: "invalid body: " + err.Error(), ExitCode: 1})
+				return
+			}
+		}
+
+		// input
+		var stdout, stderr bytes.Buffer
+		input := Input{
+			CommandInput: CommandInput{Chain: chain[:len(chain)-1], Command: chain[len(chain)-1]},
+			Flags: serveFlags(req.Flags),
+			Options: serveOptions(req.Options),
+			Args: append([]string{}, chain...),
+			Context: r.Context(),
+			Output: OutputModeJSON,
+			Reader: strings.NewReader(""),
+			Writer: &stdout,
+			ErrWriter: &stderr,
+			remote: true,
+		}
+
+		// run
+		err := c.execute(input, 0)
+		resp := ServeResponse{Output: stdout.String(), Stderr: stderr.String(), ExitCode: ExitCode(err)}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		status := http.StatusOK
+		if errors.Is(err, ErrCommandHandlerNotFound) || errors.Is(err, ErrCommandManagerNotFound) {
+			status = http.StatusNotFound
+		}
+		writeServeResponse(w, status, resp)
+	})
+	return
+}
+
+// served is the method that returns if the command of a chain is exposed by ServeHandler.
+// - hidden commands, commands of hidden groups and commands with raw args are not, since they run
+// args given by the client as is, e.g. plugins
+// - local commands are not, e.g. alias set
+func (c CLI) served(chain []string) (ok bool) {
+	finder, isFinder := c.Commander.(CommandPathFinder)
+	if !isFinder {
+		ok = true
+		return
+	}
+
+	p, err := finder.FindCommandPath(chain[len(chain)-1], chain[:len(chain)-1]...)
+	if err != nil {
+		// not found, reported by the run
+		ok = true
+		return
+	}
+	if p.Command.Hidden || p.Command.RawArgs || p.Command.Local {
+		return
+	}
+	for _, m := range p.Managers {
+		if m.Hidden {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// serveHostAllowed is the method that returns if the Host header of a request is accepted by ServeHandler.
+// - localhost, the loopback addresses and the hosts of CLI.ServeHosts are, with any port
+func (c CLI) serveHostAllowed(hostport string) (ok bool) {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+	if strings.EqualFold(host, "localhost") || contains(c.ServeHosts, host) {
+		ok = true
+		return
+	}
+	ip := net.ParseIP(host)
+	ok = ip != nil && ip.IsLoopback()
+	return
+}
+
+// writeServeResponse is the function that writes a ServeResponse as JSON.
+func writeServeResponse(w http.ResponseWriter, status int, resp ServeResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// serveFlags is the function that converts the flags of a request to the values given by the parser.
+// - scalars are converted to text, lists are joined with commas
+// - numbers are expected as json.Number, so they keep the text of the request
+func serveFlags(flags map[string]any) (f map[string]any) {
+	if len(flags) == 0 {
+		return
+	}
+
+	f = make(map[string]any, len(flags))
+	for key, value := range flags {
+		switch v := value.(type) {
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			f[key] = strings.Join(items, ",")
+		case json.Number:
+			f[key] = v.String()
+		case nil:
+			f[key] = ""
+		default:
+			f[key] = fmt.Sprint(v)
+		}
+	}
+	return
+}
+
+// serveOptions is the function that converts the options of a request to the values given by the parser.
+func serveOptions(options []string) (o map[string]int) {
+	if len(options) == 0 {
+		return
+	}
+
+	o = make(map[string]int, len(options))
+	for _, option := range options {
+		o[strings.TrimLeft(option, "-")] = 1
+	}
+	return
+}
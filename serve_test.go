@@ -0,0 +1,263 @@
+package gocli_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_ServeHandler is the test for the method ServeHandler.
+func TestCLI_ServeHandler(t *testing.T) {
+	// newServer is the function that returns the server used in the tests
+	newServer := func(hosts ...string) (srv *httptest.Server) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		deploy := cm.Group("deploy", "deploy commands")
+		deploy.AddCommand(gocli.Command{
+			Name: "status",
+			Flags: gocli.Flags{
+				{Name: "env", Required: true},
+				{Name: "limit", Type: gocli.FlagTypeInt, Default: "10"},
+			},
+			Handler: func(i gocli.Input) (err error) {
+				fmt.Fprintf(i.Writer, "env=%v limit=%v options=%v output=%s", i.Flags["env"], i.Flags["limit"], i.Options, i.Output)
+				return
+			},
+		})
+		deploy.AddCommand(gocli.Command{
+			Name: "fail",
+			Handler: func(i gocli.Input) (err error) {
+				err = &gocli.ExitError{Code: 4, Err: errors.New("deploy failed")}
+				return
+			},
+		})
+		deploy.AddCommand(gocli.Command{Name: "secret", Hidden: true, Handler: func(i gocli.Input) (err error) { return }})
+		deploy.AddCommand(gocli.Command{Name: "exec", RawArgs: true, Handler: func(i gocli.Input) (err error) { return }})
+		deploy.AddCommand(gocli.Command{Name: "forget", Local: true, Handler: func(i gocli.Input) (err error) { return }})
+		cli := gocli.NewCLI(nil, cm)
+		cli.ServeHosts = hosts
+		srv = httptest.NewServer(cli.ServeHandler())
+		return
+	}
+
+	// post is the function that posts a body and decodes the response
+	post := func(t *testing.T, url, body string) (status int, resp gocli.ServeResponse) {
+		r, err := http.Post(url, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer r.Body.Close()
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&resp))
+		status = r.StatusCode
+		return
+	}
+
+	t.Run("success - case 01: command is run with the flags of the body", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/status", `{"flags": {"env": "prod", "limit": 5}, "options": ["O1"]}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, gocli.ServeResponse{Output: "env=prod limit=5 options=map[O1:1] output=json"}, resp)
+	})
+
+	t.Run("success - case 02: flag defaults are applied", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/status", `{"flags": {"env": "dev"}}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "env=dev limit=10 options=map[] output=json", resp.Output)
+	})
+
+	t.Run("success - case 03: large numbers are given as written", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/status", `{"flags": {"env": "prod", "limit": 12345678901234567}}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "env=prod limit=12345678901234567 options=map[] output=json", resp.Output)
+	})
+
+	t.Run("success - case 04: host of ServeHosts is accepted", func(t *testing.T) {
+		// arrange
+		srv := newServer("cli.internal")
+		defer srv.Close()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/deploy/status", strings.NewReader(`{"flags": {"env": "prod"}}`))
+		require.NoError(t, err)
+		req.Host = "cli.internal:8080"
+		req.Header.Set("Content-Type", "application/json")
+
+		// act
+		r, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		r.Body.Close()
+
+		// assert
+		require.Equal(t, http.StatusOK, r.StatusCode)
+	})
+
+	t.Run("failure - case 01: command error and exit code", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/fail", ``)
+
+		// assert
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, gocli.ServeResponse{Error: "deploy failed", ExitCode: 4}, resp)
+	})
+
+	t.Run("failure - case 02: flag validation fails", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/status", `{}`)
+
+		// assert
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, gocli.ServeResponse{Error: "deploy status: flag is required: --env", ExitCode: 1}, resp)
+	})
+
+	t.Run("failure - case 03: command not found", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/unknown", `{}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, status)
+		require.Equal(t, gocli.ServeResponse{Error: "command not found", ExitCode: 1}, resp)
+	})
+
+	t.Run("failure - case 04: invalid body", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/status", `{"flags":`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, 1, resp.ExitCode)
+		require.Contains(t, resp.Error, "invalid body")
+	})
+
+	t.Run("failure - case 05: method not allowed", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		r, err := http.Get(srv.URL + "/deploy/status")
+		require.NoError(t, err)
+		r.Body.Close()
+
+		// assert
+		require.Equal(t, http.StatusMethodNotAllowed, r.StatusCode)
+		require.Equal(t, http.MethodPost, r.Header.Get("Allow"))
+	})
+
+	t.Run("failure - case 06: hidden commands and commands with raw args are not exposed", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		statusHidden, respHidden := post(t, srv.URL+"/deploy/secret", `{}`)
+		statusRaw, respRaw := post(t, srv.URL+"/deploy/exec", `{}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, statusHidden)
+		require.Equal(t, gocli.ServeResponse{Error: "command not found", ExitCode: 1}, respHidden)
+		require.Equal(t, http.StatusNotFound, statusRaw)
+		require.Equal(t, gocli.ServeResponse{Error: "command not found", ExitCode: 1}, respRaw)
+	})
+
+	t.Run("failure - case 07: body too large", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/status", `{"flags": {"env": "`+strings.Repeat("x", 2<<20)+`"}}`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, resp.Error, "request body too large")
+	})
+
+	t.Run("failure - case 08: local commands are not exposed", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		status, resp := post(t, srv.URL+"/deploy/forget", `{}`)
+
+		// assert
+		require.Equal(t, http.StatusNotFound, status)
+		require.Equal(t, gocli.ServeResponse{Error: "command not found", ExitCode: 1}, resp)
+	})
+	t.Run("failure - case 09: content type is not json", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+
+		// act
+		r, err := http.Post(srv.URL+"/deploy/status", "text/plain", strings.NewReader(`{"flags": {"env": "prod"}}`))
+		require.NoError(t, err)
+		defer r.Body.Close()
+		var resp gocli.ServeResponse
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&resp))
+
+		// assert
+		require.Equal(t, http.StatusUnsupportedMediaType, r.StatusCode)
+		require.Equal(t, gocli.ServeResponse{Error: "content type must be application/json", ExitCode: 1}, resp)
+	})
+
+	t.Run("failure - case 10: host is not a loopback host", func(t *testing.T) {
+		// arrange
+		srv := newServer()
+		defer srv.Close()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/deploy/status", strings.NewReader(`{"flags": {"env": "prod"}}`))
+		require.NoError(t, err)
+		req.Host = "attacker.example.com"
+		req.Header.Set("Content-Type", "application/json")
+
+		// act
+		r, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer r.Body.Close()
+		var resp gocli.ServeResponse
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&resp))
+
+		// assert
+		require.Equal(t, http.StatusForbidden, r.StatusCode)
+		require.Equal(t, gocli.ServeResponse{Error: "host not allowed", ExitCode: 1}, resp)
+	})
+}
@@ -0,0 +1,112 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	// ErrInvalidColorMode is the error that returns when the color mode is invalid.
+	ErrInvalidColorMode = errors.New("invalid color mode")
+)
+
+// ColorMode is the type that represents when the output should be styled.
+type ColorMode string
+
+const (
+	// ColorModeAuto styles the output only when it is a terminal and NO_COLOR is not set.
+	ColorModeAuto ColorMode = "auto"
+	// ColorModeAlways always styles the output.
+	ColorModeAlways ColorMode = "always"
+	// ColorModeNever never styles the output.
+	ColorModeNever ColorMode = "never"
+)
+
+// ParseColorMode is the function that parses a color mode, as given to the --color flag.
+func ParseColorMode(value string) (m ColorMode, err error) {
+	switch ColorMode(value) {
+	case ColorModeAuto, ColorModeAlways, ColorModeNever:
+		m = ColorMode(value)
+	case "":
+		m = ColorModeAuto
+	default:
+		err = fmt.Errorf("%w: %q (expected auto, always or never)", ErrInvalidColorMode, value)
+	}
+	return
+}
+
+// Color is the type that represents an ANSI foreground color.
+type Color int
+
+const (
+	ColorRed     Color = 31
+	ColorGreen   Color = 32
+	ColorYellow  Color = 33
+	ColorBlue    Color = 34
+	ColorMagenta Color = 35
+	ColorCyan    Color = 36
+)
+
+// NewStyler is the function that returns a new Styler for the given writer.
+// - in auto mode styling is enabled only if w is a terminal, NO_COLOR is not set and TERM is not dumb
+func NewStyler(w io.Writer, mode ColorMode) (s Styler) {
+	switch mode {
+	case ColorModeAlways:
+		s.enabled = true
+	case ColorModeNever:
+		s.enabled = false
+	default:
+		s.enabled = isTerminal(w) && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	}
+	return
+}
+
+// Styler is the struct that styles text with ANSI escape sequences.
+// The zero value is a Styler with styling disabled, which returns the text as is.
+type Styler struct {
+	// enabled is the flag that indicates if the styling is applied.
+	enabled bool
+}
+
+// Enabled is the method that returns if the styling is applied.
+func (s Styler) Enabled() (ok bool) {
+	ok = s.enabled
+	return
+}
+
+// Bold is the method that styles the text as bold.
+func (s Styler) Bold(text string) (r string) {
+	r = s.wrap("1", text)
+	return
+}
+
+// Dim is the method that styles the text as dimmed.
+func (s Styler) Dim(text string) (r string) {
+	r = s.wrap("2", text)
+	return
+}
+
+// Emphasis is the method that styles the text as emphasized (italic).
+func (s Styler) Emphasis(text string) (r string) {
+	r = s.wrap("3", text)
+	return
+}
+
+// Color is the method that styles the text with a foreground color.
+func (s Styler) Color(c Color, text string) (r string) {
+	r = s.wrap(fmt.Sprintf("%d", c), text)
+	return
+}
+
+// wrap is the method that wraps the text with the escape sequence of the given code.
+func (s Styler) wrap(code string, text string) (r string) {
+	if !s.enabled || text == "" {
+		r = text
+		return
+	}
+
+	r = "\x1b[" + code + "m" + text + "\x1b[0m"
+	return
+}
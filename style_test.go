@@ -0,0 +1,107 @@
+package gocli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewStyler is the test for the function NewStyler.
+func TestNewStyler(t *testing.T) {
+	t.Run("success - case 01: auto mode - writer is not a terminal", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		s := gocli.NewStyler(w, gocli.ColorModeAuto)
+
+		// assert
+		require.False(t, s.Enabled())
+		require.Equal(t, "text", s.Bold("text"))
+	})
+
+	t.Run("success - case 02: auto mode - writer is a regular file", func(t *testing.T) {
+		// arrange
+		f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		// act
+		s := gocli.NewStyler(f, gocli.ColorModeAuto)
+
+		// assert
+		require.False(t, s.Enabled())
+	})
+
+	t.Run("success - case 03: always mode", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		s := gocli.NewStyler(w, gocli.ColorModeAlways)
+
+		// assert
+		require.True(t, s.Enabled())
+		require.Equal(t, "\x1b[1mtext\x1b[0m", s.Bold("text"))
+		require.Equal(t, "\x1b[2mtext\x1b[0m", s.Dim("text"))
+		require.Equal(t, "\x1b[3mtext\x1b[0m", s.Emphasis("text"))
+		require.Equal(t, "\x1b[31mtext\x1b[0m", s.Color(gocli.ColorRed, "text"))
+	})
+
+	t.Run("success - case 04: never mode", func(t *testing.T) {
+		// arrange
+		w := &bytes.Buffer{}
+
+		// act
+		s := gocli.NewStyler(w, gocli.ColorModeNever)
+
+		// assert
+		require.False(t, s.Enabled())
+		require.Equal(t, "text", s.Color(gocli.ColorRed, "text"))
+	})
+
+	t.Run("success - case 05: zero value is disabled", func(t *testing.T) {
+		// arrange
+		var s gocli.Styler
+
+		// act
+		r := s.Dim("text")
+
+		// assert
+		require.Equal(t, "text", r)
+	})
+}
+
+// TestParseColorMode is the test for the function ParseColorMode.
+func TestParseColorMode(t *testing.T) {
+	t.Run("success - case 01: valid modes", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		m1, err1 := gocli.ParseColorMode("never")
+		m2, err2 := gocli.ParseColorMode("")
+
+		// assert
+		require.NoError(t, err1)
+		require.Equal(t, gocli.ColorModeNever, m1)
+		require.NoError(t, err2)
+		require.Equal(t, gocli.ColorModeAuto, m2)
+	})
+
+	t.Run("failure - case 01: invalid mode", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		_, err := gocli.ParseColorMode("rainbow")
+
+		// assert
+		require.Error(t, err)
+		require.ErrorIs(t, err, gocli.ErrInvalidColorMode)
+	})
+}
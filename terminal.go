@@ -0,0 +1,36 @@
+package gocli
+
+import "os"
+
+// terminalStream is the interface implemented by the readers and writers that stand for a terminal of another process,
+// e.g. the stdio of a daemon client.
+type terminalStream interface {
+	// terminal returns if the stream is a terminal.
+	terminal() (ok bool)
+}
+
+// isTerminal is the function that checks if a reader or writer is a terminal.
+// - only an *os.File backed by a character device is considered a terminal,
+// so any injected reader or writer (buffers, pipes, files) is not one
+// - a terminalStream reports it itself, e.g. the stdio forwarded by a daemon client
+func isTerminal(v any) (ok bool) {
+	if t, isStream := v.(terminalStream); isStream {
+		ok = t.terminal()
+		return
+	}
+
+	f, ok := v.(*os.File)
+	if !ok || f == nil {
+		ok = false
+		return
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		ok = false
+		return
+	}
+
+	ok = info.Mode()&os.ModeCharDevice != 0
+	return
+}
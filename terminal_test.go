@@ -0,0 +1,99 @@
+package gocli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_ServeDaemon_Terminal is the test for the terminal state forwarded by the clients of the daemon.
+func TestCLI_ServeDaemon_Terminal(t *testing.T) {
+	// run is the function that runs a command in a daemon for a client with the given terminal state
+	run := func(t *testing.T, req daemonMessage) (in Input, mode ProgressMode) {
+		// - daemon
+		cm := NewCommanderManager("app", "app description")
+		cm.AddCommand(Command{
+			Name: "tty",
+			Handler: func(i Input) (err error) {
+				in = i
+				p := i.Progress(optional.None[ConfigProgress]())
+				p.Stop()
+				mode = p.mode
+				return
+			},
+		})
+		cli := NewCLI(NewParserDefault(optional.None[ConfigParserDefault]()), cm)
+		dir, err := os.MkdirTemp("", "gocli")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		cfg := ConfigDaemon{Socket: filepath.Join(dir, "d.sock"), IdleTimeout: 50 * time.Millisecond}
+		done := make(chan error, 1)
+		go func() { done <- cli.ServeDaemon(context.Background(), optional.Some(cfg)) }()
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(cfg.Socket)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		// - client
+		conn, dec, _, err := dialDaemon(cfg.Socket)
+		require.NoError(t, err)
+		defer conn.Close()
+		enc := json.NewEncoder(conn)
+		require.NoError(t, enc.Encode(req))
+		require.NoError(t, enc.Encode(daemonMessage{Stream: "stdin", EOF: true}))
+		for {
+			var msg daemonMessage
+			require.NoError(t, dec.Decode(&msg))
+			if msg.Done {
+				require.Equal(t, 0, msg.Code, msg.Error)
+				break
+			}
+		}
+		conn.Close()
+		require.NoError(t, <-done)
+		return
+	}
+
+	t.Run("success - case 01: stdio of the client are terminals", func(t *testing.T) {
+		// arrange
+		req := daemonMessage{Args: []string{"tty"}, Env: []string{"TERM=xterm"}, StdinTerminal: true, StdoutTerminal: true, StderrTerminal: true}
+
+		// act
+		in, mode := run(t, req)
+
+		// assert
+		require.True(t, in.Interactive)
+		require.True(t, in.Style.Enabled())
+		require.Equal(t, ProgressModeTerminal, mode)
+	})
+
+	t.Run("success - case 02: stdio of the client are not terminals", func(t *testing.T) {
+		// arrange
+		req := daemonMessage{Args: []string{"tty"}, Env: []string{"TERM=xterm"}}
+
+		// act
+		in, mode := run(t, req)
+
+		// assert
+		require.False(t, in.Interactive)
+		require.False(t, in.Style.Enabled())
+		require.Equal(t, ProgressModeLog, mode)
+	})
+
+	t.Run("failure - case 01: password is not read from the terminal of the client", func(t *testing.T) {
+		// arrange
+		r := &daemonStdin{isTerminal: true}
+
+		// act
+		err := setEcho(r, false)
+
+		// assert
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,130 @@
+package gocli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidTimeout is the error that returns when the value of the --timeout flag is invalid.
+	ErrInvalidTimeout = errors.New("invalid timeout")
+	// errHandlerTimeout is the cause of the cancellation of the context of a handler by its own timeout.
+	errHandlerTimeout = errors.New("handler timeout")
+)
+
+// ExitCodeTimeout is the exit code of a run whose handler timed out.
+// - it is the exit code of the timeout command of coreutils
+const ExitCodeTimeout = 124
+
+// DefaultGracePeriod is the time a handler has to return after its context is cancelled, by a timeout or an interrupt.
+const DefaultGracePeriod = 5 * time.Second
+
+// TimeoutError is the struct that represents a command handler that ran out of time.
+type TimeoutError struct {
+	// Command is the command chain, e.g. "db migrate".
+	Command string
+	// Timeout is the timeout of the command.
+	Timeout time.Duration
+	// Forced is the flag that indicates if the handler did not return within the grace period.
+	// - the handler is left running, the process is meant to exit
+	Forced bool
+	// Err is the error returned by the handler after its context was cancelled, nil if it did not return.
+	Err error
+}
+
+// Error is the method that returns the message of the error.
+func (e *TimeoutError) Error() (msg string) {
+	msg = fmt.Sprintf("%s: timed out after %s", e.Command, e.Timeout)
+	if e.Forced {
+		msg += ", the command did not stop within the grace period"
+	}
+	return
+}
+
+// Unwrap is the method that returns the errors wrapped: context.DeadlineExceeded and the error of the handler.
+func (e *TimeoutError) Unwrap() (errs []error) {
+	errs = []error{context.DeadlineExceeded}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return
+}
+
+// ParseTimeout is the function that parses the value of the --timeout flag, a positive duration, e.g. 30s.
+func ParseTimeout(value string) (d time.Duration, err error) {
+	d, err = time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		d = 0
+		err = fmt.Errorf("%w: %q (expected a positive duration, e.g. 30s)", ErrInvalidTimeout, value)
+	}
+	return
+}
+
+// callHandlerTimeout is the method that runs a command handler with a timeout, every run goes through it.
+// - the context of the handler is cancelled when the timeout runs out or the parent context is done (e.g. on interrupt),
+// then the handler has the grace period to return
+// - a timeout of 0 is no timeout, only the parent context cancels the handler
+// - a handler that does not return within the grace period is left running
+// - the error is a TimeoutError only if the timeout ran out, not if the parent context was done
+func (c CLI) callHandlerTimeout(handler CommandHandler, input Input, timeout time.Duration) (err error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(input.Context, timeout, errHandlerTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(input.Context)
+	}
+	defer cancel()
+	input.Context = ctx
+	command := commandAttr(input.CommandInput).Value.String()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.callHandler(handler, input)
+	}()
+
+	// handler returned in time, or its context was done
+	var returned bool
+	select {
+	case err = <-done:
+		returned = true
+	case <-ctx.Done():
+	}
+	timedOut := func() (ok bool) {
+		ok = errors.Is(context.Cause(ctx), errHandlerTimeout)
+		return
+	}
+	if returned && (err == nil || !timedOut()) {
+		return
+	}
+
+	// context done
+	// - the handler has the grace period to return
+	if !returned {
+		grace := c.GracePeriod
+		if grace == 0 {
+			grace = DefaultGracePeriod
+		}
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case err = <-done:
+		case <-timer.C:
+			input.Logger.Warn("handler did not stop within the grace period", "grace_period", grace)
+			if timedOut() {
+				err = &TimeoutError{Command: command, Timeout: timeout, Forced: true}
+				return
+			}
+			err = fmt.Errorf("%s: the command did not stop within the grace period: %w", command, ctx.Err())
+			return
+		}
+	}
+	if !timedOut() {
+		return
+	}
+	err = &TimeoutError{Command: command, Timeout: timeout, Err: err}
+	return
+}
@@ -0,0 +1,197 @@
+package gocli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCLI_Run_Timeout is the test for the timeouts of the command handlers.
+func TestCLI_Run_Timeout(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	// - the command waits for its context to be done, then returns after the cleanup time
+	// - the command "quick" returns at once
+	newCLI := func(timeout, cleanup time.Duration) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{
+			Name:    "migrate",
+			Timeout: timeout,
+			Handler: func(i gocli.Input) (err error) {
+				<-i.Context.Done()
+				time.Sleep(cleanup)
+				err = i.Context.Err()
+				return
+			},
+		})
+		db.AddCommand(gocli.Command{
+			Name:    "quick",
+			Timeout: timeout,
+			Handler: func(i gocli.Input) (err error) { return },
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.ErrWriter = &bytes.Buffer{}
+		cli.GracePeriod = time.Second
+		return
+	}
+
+	t.Run("success - case 01: handler returns before the timeout", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "quick"}
+		cli := newCLI(time.Second, 0)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("failure - case 01: timeout of the command cancels the context", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(10*time.Millisecond, 0)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		var timeoutErr *gocli.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.False(t, timeoutErr.Forced)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.EqualError(t, err, "db migrate: timed out after 10ms")
+		require.Equal(t, gocli.ExitCodeTimeout, gocli.ExitCode(err))
+	})
+
+	t.Run("failure - case 02: --timeout overrides the timeout of the command", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "--timeout", "20ms", "db", "migrate"}
+		cli := newCLI(time.Hour, 0)
+
+		// act
+		start := time.Now()
+		err := cli.Run()
+
+		// assert
+		require.EqualError(t, err, "db migrate: timed out after 20ms")
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("failure - case 03: handler does not stop within the grace period", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(10*time.Millisecond, time.Hour)
+		cli.GracePeriod = 10 * time.Millisecond
+
+		// act
+		err := cli.Run()
+
+		// assert
+		var timeoutErr *gocli.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.True(t, timeoutErr.Forced)
+		require.EqualError(t, err, "db migrate: timed out after 10ms, the command did not stop within the grace period")
+		require.Equal(t, gocli.ExitCodeTimeout, gocli.ExitCode(err))
+	})
+
+	t.Run("failure - case 04: invalid --timeout", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "--timeout", "-1s", "db", "quick"}
+		cli := newCLI(0, 0)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidTimeout)
+		require.EqualError(t, err, "invalid timeout: \"-1s\" (expected a positive duration, e.g. 30s)")
+	})
+
+	t.Run("failure - case 05: timeout of the CLI does not replace the timeout of the command", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(20*time.Millisecond, 0)
+		cli.Timeout = time.Hour
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.EqualError(t, err, "db migrate: timed out after 20ms")
+	})
+
+	t.Run("failure - case 06: timeout of the CLI applies to the commands without one", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(0, 0)
+		cli.Timeout = 10 * time.Millisecond
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.EqualError(t, err, "db migrate: timed out after 10ms")
+	})
+
+	t.Run("failure - case 07: deadline of the parent context is not the timeout of the command", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(time.Hour, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := cli.RunContext(ctx)
+
+		// assert
+		var timeoutErr *gocli.TimeoutError
+		require.False(t, errors.As(err, &timeoutErr))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("failure - case 08: grace period applies when the parent context is cancelled", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(time.Hour, time.Hour)
+		cli.GracePeriod = 10 * time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		// act
+		start := time.Now()
+		err := cli.RunContext(ctx)
+
+		// assert
+		require.ErrorIs(t, err, context.Canceled)
+		require.EqualError(t, err, "db migrate: the command did not stop within the grace period: context canceled")
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("failure - case 09: grace period applies when the parent context is cancelled without a timeout", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate"}
+		cli := newCLI(0, time.Hour)
+		cli.GracePeriod = 10 * time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		// act
+		start := time.Now()
+		err := cli.RunContext(ctx)
+
+		// assert
+		var timeoutErr *gocli.TimeoutError
+		require.False(t, errors.As(err, &timeoutErr))
+		require.ErrorIs(t, err, context.Canceled)
+		require.EqualError(t, err, "db migrate: the command did not stop within the grace period: context canceled")
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,228 @@
+package gocli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tracer is the interface that starts the spans of the phases of a run: parse, resolve and handler.
+// It has the shape of the tracer of OpenTelemetry, so an adapter to it is a few lines.
+type Tracer interface {
+	// Start is the method that starts a span, child of the span of the context if any.
+	// - the returned context carries the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is the interface that represents a phase of a run.
+type Span interface {
+	// SetAttributes is the method that sets attributes of the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError is the method that records the error of the span.
+	RecordError(err error)
+	// End is the method that ends the span.
+	End()
+}
+
+// Attribute is the struct that represents an attribute of a span.
+type Attribute struct {
+	// Key is the key of the attribute, e.g. "gocli.command".
+	Key string
+	// Value is the value of the attribute: a string, a bool, an int or a []string.
+	Value any
+}
+
+// Span names of the phases of a run.
+const (
+	// SpanRun is the span of the whole run, parent of the other ones.
+	SpanRun = "gocli.run"
+	// SpanParse is the span of the parsing of the args.
+	SpanParse = "gocli.parse"
+	// SpanResolve is the span of the resolution of the command, including the checks of its flags.
+	SpanResolve = "gocli.resolve"
+	// SpanHandler is the span of the command handler.
+	SpanHandler = "gocli.handler"
+)
+
+// Attribute keys set by the CLI.
+const (
+	// AttributeCommand is the command chain, e.g. "db migrate".
+	AttributeCommand = "gocli.command"
+	// AttributeFlags are the names of the flags given, never their values.
+	AttributeFlags = "gocli.flags"
+)
+
+// tracer is the method that returns the tracer of the CLI.
+// - it defaults to a tracer that does nothing
+func (c CLI) tracer() (t Tracer) {
+	t = c.Tracer
+	if t == nil {
+		t = noopTracer{}
+	}
+	return
+}
+
+// endSpan is the function that records the error of a span, if any, and ends it.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// inputAttributes is the function that returns the attributes of an input: its command chain and flag names.
+func inputAttributes(input Input) (attrs []Attribute) {
+	names := make([]string, 0, len(input.Flags))
+	for name := range input.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs = []Attribute{
+		{Key: AttributeCommand, Value: commandAttr(input.CommandInput).Value.String()},
+		{Key: AttributeFlags, Value: names},
+	}
+	return
+}
+
+// noopTracer is the tracer that does nothing.
+type noopTracer struct{}
+
+// Start is the method that returns the context as is and a span that does nothing.
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan is the span that does nothing.
+type noopSpan struct{}
+
+// SetAttributes is the method that does nothing.
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+
+// RecordError is the method that does nothing.
+func (noopSpan) RecordError(err error) {}
+
+// End is the method that does nothing.
+func (noopSpan) End() {}
+
+// NewStdoutTracer is the function that returns a tracer writing each ended span as a JSON line to w.
+// - it is meant for local debugging, e.g. NewStdoutTracer(os.Stderr)
+func NewStdoutTracer(w io.Writer) (t *StdoutTracer) {
+	t = &StdoutTracer{w: w, now: time.Now}
+	return
+}
+
+// StdoutTracer is the struct that writes spans as JSON lines.
+type StdoutTracer struct {
+	// mu is the mutex that guards the writer.
+	mu sync.Mutex
+	// w is the writer of the spans.
+	w io.Writer
+	// now is the function that returns the current time.
+	now func() time.Time
+}
+
+// stdoutSpanKey is the key of the current span in a context.
+type stdoutSpanKey struct{}
+
+// Start is the method that starts a span.
+func (t *StdoutTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s := &stdoutSpan{tracer: t, start: t.now(), attrs: map[string]any{}}
+	s.record.Name = name
+	s.record.SpanID = randomHex(8)
+	if parent, ok := ctx.Value(stdoutSpanKey{}).(*stdoutSpan); ok {
+		s.record.TraceID = parent.record.TraceID
+		s.record.ParentID = parent.record.SpanID
+	} else {
+		s.record.TraceID = randomHex(16)
+	}
+
+	return context.WithValue(ctx, stdoutSpanKey{}, s), s
+}
+
+// StdoutSpan is the struct that represents a span as written by a StdoutTracer.
+type StdoutSpan struct {
+	// Name is the name of the span.
+	Name string `json:"name"`
+	// TraceID is the id shared by the spans of a run.
+	TraceID string `json:"trace_id"`
+	// SpanID is the id of the span.
+	SpanID string `json:"span_id"`
+	// ParentID is the id of the parent span, empty for the root.
+	ParentID string `json:"parent_id,omitempty"`
+	// Start is the start time of the span.
+	Start time.Time `json:"start"`
+	// Duration is the duration of the span, e.g. "1.5ms".
+	Duration string `json:"duration"`
+	// Attributes are the attributes of the span.
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// Error is the error of the span.
+	Error string `json:"error,omitempty"`
+}
+
+// stdoutSpan is the span of a StdoutTracer.
+type stdoutSpan struct {
+	// mu is the mutex that guards the span.
+	mu sync.Mutex
+	// tracer is the tracer of the span.
+	tracer *StdoutTracer
+	// start is the start time of the span.
+	start time.Time
+	// attrs are the attributes of the span.
+	attrs map[string]any
+	// record is the span as written.
+	record StdoutSpan
+	// ended is the flag that tells if the span was already written.
+	ended bool
+}
+
+// SetAttributes is the method that sets attributes of the span.
+func (s *stdoutSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+// RecordError is the method that records the error of the span.
+func (s *stdoutSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.record.Error = err.Error()
+}
+
+// End is the method that writes the span.
+func (s *stdoutSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	record := s.record
+	record.Start = s.start
+	record.Duration = s.tracer.now().Sub(s.start).String()
+	if len(s.attrs) > 0 {
+		record.Attributes = s.attrs
+	}
+	s.mu.Unlock()
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.w.Write(append(b, '\n'))
+}
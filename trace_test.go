@@ -0,0 +1,176 @@
+package gocli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/LNMMusic/optional"
+	"github.com/stretchr/testify/require"
+)
+
+// spanRecorder is the tracer that records the spans of the tests.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+// recordedSpan is a span recorded by a spanRecorder.
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+// recordedSpanKey is the key of the current span in a context.
+type recordedSpanKey struct{}
+
+func (r *spanRecorder) Start(ctx context.Context, name string) (context.Context, gocli.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := &recordedSpan{name: name, attrs: map[string]any{}}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*recordedSpan); ok {
+		s.parent = parent.name
+	}
+	r.spans = append(r.spans, s)
+	return context.WithValue(ctx, recordedSpanKey{}, s), s
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...gocli.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+
+func (s *recordedSpan) End() { s.ended = true }
+
+// TestCLI_Run_Tracer is the test for the tracing of the method Run.
+func TestCLI_Run_Tracer(t *testing.T) {
+	// newCLI is the function that returns the CLI used in the tests
+	newCLI := func(tracer gocli.Tracer) (cli gocli.CLI) {
+		cm := gocli.NewCommanderManager("app", "app description")
+		db := cm.Group("db", "database commands")
+		db.AddCommand(gocli.Command{
+			Name:  "migrate",
+			Flags: gocli.Flags{{Name: "env"}, {Name: "steps", Type: gocli.FlagTypeInt, Default: "1"}},
+			Handler: func(i gocli.Input) (err error) {
+				// child span of the handler, e.g. a call to a service
+				_, span := tracer.Start(i.Context, "service.call")
+				span.End()
+				if i.Flags["env"] == "prod" {
+					err = errors.New("migration failed")
+				}
+				return
+			},
+		})
+		cli = gocli.NewCLI(gocli.NewParserDefault(optional.None[gocli.ConfigParserDefault]()), cm)
+		cli.Tracer = tracer
+		return
+	}
+
+	t.Run("success - case 01: spans of the phases are emitted with the command chain and flag names", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--env", "dev"}
+		tracer := &spanRecorder{}
+		cli := newCLI(tracer)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, tracer.spans, 5)
+		names := []string{}
+		for _, s := range tracer.spans {
+			require.True(t, s.ended)
+			require.NoError(t, s.err)
+			names = append(names, s.name+"<"+s.parent)
+		}
+		require.Equal(t, []string{
+			"gocli.run<",
+			"gocli.parse<gocli.run",
+			"gocli.resolve<gocli.run",
+			"gocli.handler<gocli.run",
+			"service.call<gocli.handler",
+		}, names)
+		require.Equal(t, map[string]any{"gocli.command": "db migrate", "gocli.flags": []string{"env"}}, tracer.spans[0].attrs)
+		require.Equal(t, map[string]any{"gocli.command": "db migrate", "gocli.flags": []string{"env", "steps"}}, tracer.spans[3].attrs)
+	})
+
+	t.Run("success - case 02: errors are recorded", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "migrate", "--env", "prod"}
+		tracer := &spanRecorder{}
+		cli := newCLI(tracer)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.EqualError(t, err, "migration failed")
+		require.EqualError(t, tracer.spans[0].err, "migration failed")
+		require.EqualError(t, tracer.spans[3].err, "migration failed")
+		require.NoError(t, tracer.spans[2].err)
+	})
+
+	t.Run("success - case 03: resolution errors are recorded", func(t *testing.T) {
+		// arrange
+		os.Args = []string{"app.exe", "db", "rollback"}
+		tracer := &spanRecorder{}
+		cli := newCLI(tracer)
+
+		// act
+		err := cli.Run()
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrCommandHandlerNotFound)
+		require.Len(t, tracer.spans, 3)
+		require.Equal(t, gocli.SpanResolve, tracer.spans[2].name)
+		require.ErrorIs(t, tracer.spans[2].err, gocli.ErrCommandHandlerNotFound)
+	})
+}
+
+// TestStdoutTracer is the test for the stdout tracer.
+func TestStdoutTracer(t *testing.T) {
+	t.Run("success - case 01: spans are written as json lines", func(t *testing.T) {
+		// arrange
+		var out bytes.Buffer
+		tracer := gocli.NewStdoutTracer(&out)
+
+		// act
+		ctx, parent := tracer.Start(context.Background(), "parent")
+		_, child := tracer.Start(ctx, "child")
+		child.SetAttributes(gocli.Attribute{Key: "gocli.command", Value: "db migrate"})
+		child.RecordError(errors.New("boom"))
+		child.End()
+		child.End()
+		parent.End()
+
+		// assert
+		dec := json.NewDecoder(&out)
+		var spans []gocli.StdoutSpan
+		for dec.More() {
+			var s gocli.StdoutSpan
+			require.NoError(t, dec.Decode(&s))
+			spans = append(spans, s)
+		}
+		require.Len(t, spans, 2)
+		require.Equal(t, "child", spans[0].Name)
+		require.Equal(t, "boom", spans[0].Error)
+		require.Equal(t, map[string]any{"gocli.command": "db migrate"}, spans[0].Attributes)
+		require.Equal(t, "parent", spans[1].Name)
+		require.Equal(t, spans[1].TraceID, spans[0].TraceID)
+		require.Equal(t, spans[1].SpanID, spans[0].ParentID)
+		require.Empty(t, spans[1].ParentID)
+	})
+}
@@ -0,0 +1,260 @@
+package gocli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidByteSize is the error that returns when a byte size can not be parsed.
+	ErrInvalidByteSize = errors.New("invalid byte size")
+	// ErrInvalidKeyValue is the error that returns when a key=value pair can not be parsed.
+	ErrInvalidKeyValue = errors.New("invalid key=value pair")
+)
+
+// Value is the interface that a custom type of a flag value implements.
+// - it is like the flag.Value of the standard library, plus the name of the type
+type Value interface {
+	// Set is the method that parses the value given in the command line.
+	Set(value string) error
+	// String is the method that returns the value as text.
+	String() string
+	// Type is the method that returns the name of the type, e.g. "bytes".
+	Type() string
+}
+
+// ValueCompleter is the interface that wraps the method to complete the value of a flag.
+// It is optional for a Value, the allowed values of the flag are completed otherwise.
+type ValueCompleter interface {
+	// Complete is the method that returns the candidates for a partial value.
+	Complete(partial string) (candidates []string)
+}
+
+// ValueHelper is the interface that wraps the method to describe the format of the value of a flag.
+// It is optional for a Value, the name of its type is shown in the help otherwise.
+type ValueHelper interface {
+	// Help is the method that returns the format of the value, e.g. "size, e.g. 10MiB".
+	Help() string
+}
+
+// applyFlagValues is the function that replaces the values of the flags with a custom type by the parsed Value.
+// - it is called after the validation, so the values are known to be valid
+func applyFlagValues(specs Flags, flags map[string]any) (r map[string]any) {
+	r = flags
+	for _, f := range specs {
+		if f.Value == nil {
+			continue
+		}
+		value, ok := r[f.Name]
+		if !ok {
+			continue
+		}
+		v := f.Value()
+		if err := v.Set(fmt.Sprint(value)); err != nil {
+			continue
+		}
+		r[f.Name] = v
+	}
+	return
+}
+
+// NewStdValue is the function that adapts a flag.Value of the standard library to a Value.
+// - typ is the name of the type, shown in help and manifests
+func NewStdValue(typ string, v flag.Value) (s *StdValue) {
+	s = &StdValue{Value: v, Name: typ}
+	return
+}
+
+// StdValue is the struct that adapts a flag.Value of the standard library to a Value.
+type StdValue struct {
+	// Value is the adapted value.
+	flag.Value
+	// Name is the name of the type.
+	// - default: value
+	Name string
+}
+
+// Type is the method that returns the name of the type.
+func (s *StdValue) Type() (t string) {
+	t = s.Name
+	if t == "" {
+		t = "value"
+	}
+	return
+}
+
+// byteSizeUnits are the units of a byte size, both decimal and binary.
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"KB":  1000,
+	"MB":  1000 * 1000,
+	"GB":  1000 * 1000 * 1000,
+	"TB":  1000 * 1000 * 1000 * 1000,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// NewByteSize is the function that returns a new byte size value, e.g. for Flag.Value.
+func NewByteSize() (v Value) {
+	v = new(ByteSize)
+	return
+}
+
+// ByteSize is the type that represents a size in bytes, given as e.g. 512, 10MB or 10MiB.
+type ByteSize int64
+
+// Set is the method that parses a byte size.
+// - the units are case insensitive, KB is 1000 bytes and KiB is 1024 bytes
+func (b *ByteSize) Set(value string) (err error) {
+	value = strings.TrimSpace(value)
+	i := strings.IndexFunc(value, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i == -1 {
+		i = len(value)
+	}
+	number, unit := value[:i], strings.ToUpper(strings.TrimSpace(value[i:]))
+
+	n, perr := strconv.ParseFloat(number, 64)
+	multiplier, ok := byteSizeUnits[unit]
+	if perr != nil || !ok {
+		err = fmt.Errorf("%w: %q", ErrInvalidByteSize, value)
+		return
+	}
+
+	// - a fraction of a byte or a size over the int64 range is rejected, e.g. 1.5B or 9000000TiB
+	size := n * float64(multiplier)
+	if size != math.Trunc(size) || size >= math.MaxInt64 {
+		err = fmt.Errorf("%w: %q", ErrInvalidByteSize, value)
+		return
+	}
+
+	*b = ByteSize(size)
+	return
+}
+
+// String is the method that returns the size in bytes.
+func (b *ByteSize) String() (s string) {
+	s = strconv.FormatInt(int64(*b), 10)
+	return
+}
+
+// Type is the method that returns the name of the type.
+func (b *ByteSize) Type() (t string) {
+	t = "bytes"
+	return
+}
+
+// Help is the method that returns the format of a byte size.
+func (b *ByteSize) Help() (h string) {
+	h = "size, e.g. 512, 10MB or 10MiB"
+	return
+}
+
+// Complete is the method that completes the unit of a byte size.
+func (b *ByteSize) Complete(partial string) (candidates []string) {
+	i := strings.IndexFunc(partial, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i == 0 || partial == "" {
+		return
+	}
+	number, unit := partial, ""
+	if i > 0 {
+		number, unit = partial[:i], partial[i:]
+	}
+	for _, u := range []string{"KB", "MB", "GB", "TB", "KiB", "MiB", "GiB", "TiB"} {
+		if strings.HasPrefix(strings.ToUpper(u), strings.ToUpper(unit)) {
+			candidates = append(candidates, number+u)
+		}
+	}
+	return
+}
+
+// NewCIDR is the function that returns a new CIDR value, e.g. for Flag.Value.
+func NewCIDR() (v Value) {
+	v = new(CIDR)
+	return
+}
+
+// CIDR is the type that represents an IP network, given as e.g. 10.0.0.0/8.
+type CIDR struct {
+	netip.Prefix
+}
+
+// Set is the method that parses an IP network.
+func (c *CIDR) Set(value string) (err error) {
+	c.Prefix, err = netip.ParsePrefix(value)
+	return
+}
+
+// Type is the method that returns the name of the type.
+func (c *CIDR) Type() (t string) {
+	t = "cidr"
+	return
+}
+
+// Help is the method that returns the format of an IP network.
+func (c *CIDR) Help() (h string) {
+	h = "network, e.g. 10.0.0.0/8"
+	return
+}
+
+// NewKeyValues is the function that returns a new key=value list value, e.g. for Flag.Value.
+func NewKeyValues() (v Value) {
+	v = &KeyValues{}
+	return
+}
+
+// KeyValues is the type that represents a list of key=value pairs, given as e.g. env=prod,team=core.
+type KeyValues map[string]string
+
+// Set is the method that parses a comma separated list of key=value pairs.
+func (kv *KeyValues) Set(value string) (err error) {
+	if *kv == nil {
+		*kv = make(KeyValues)
+	}
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			err = fmt.Errorf("%w: %q", ErrInvalidKeyValue, pair)
+			return
+		}
+		(*kv)[key] = val
+	}
+	return
+}
+
+// String is the method that returns the pairs sorted by key, separated by commas.
+func (kv *KeyValues) String() (s string) {
+	keys := make([]string, 0, len(*kv))
+	for key := range *kv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+(*kv)[key])
+	}
+	s = strings.Join(pairs, ",")
+	return
+}
+
+// Type is the method that returns the name of the type.
+func (kv *KeyValues) Type() (t string) {
+	t = "key=value"
+	return
+}
+
+// Help is the method that returns the format of a key=value list.
+func (kv *KeyValues) Help() (h string) {
+	h = "key=value pairs, separated by commas"
+	return
+}
@@ -0,0 +1,192 @@
+package gocli_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/LNMMusic/gocli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestByteSize_Set tests the method Set of the ByteSize type.
+func TestByteSize_Set(t *testing.T) {
+	t.Run("success - case 01: bytes without unit", func(t *testing.T) {
+		// arrange
+		var b gocli.ByteSize
+
+		// act
+		err := b.Set("512")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, gocli.ByteSize(512), b)
+	})
+
+	t.Run("success - case 02: decimal and binary units", func(t *testing.T) {
+		// arrange
+		var decimal, binary gocli.ByteSize
+
+		// act
+		errDecimal := decimal.Set("10MB")
+		errBinary := binary.Set("1.5kib")
+
+		// assert
+		require.NoError(t, errDecimal)
+		require.Equal(t, gocli.ByteSize(10000000), decimal)
+		require.NoError(t, errBinary)
+		require.Equal(t, gocli.ByteSize(1536), binary)
+		require.Equal(t, "1536", binary.String())
+	})
+
+	t.Run("failure - case 01: unknown unit", func(t *testing.T) {
+		// arrange
+		var b gocli.ByteSize
+
+		// act
+		err := b.Set("10XB")
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidByteSize)
+		require.EqualError(t, err, "invalid byte size: \"10XB\"")
+	})
+
+	t.Run("failure - case 02: fraction of a byte and overflow", func(t *testing.T) {
+		// arrange
+		var fraction, overflow gocli.ByteSize
+
+		// act
+		errFraction := fraction.Set("1.5B")
+		errOverflow := overflow.Set("9000000TiB")
+
+		// assert
+		require.ErrorIs(t, errFraction, gocli.ErrInvalidByteSize)
+		require.ErrorIs(t, errOverflow, gocli.ErrInvalidByteSize)
+		require.Equal(t, gocli.ByteSize(0), overflow)
+	})
+}
+
+// TestByteSize_Complete tests the method Complete of the ByteSize type.
+func TestByteSize_Complete(t *testing.T) {
+	t.Run("success - case 01: units of a number", func(t *testing.T) {
+		// arrange
+		var b gocli.ByteSize
+
+		// act
+		candidates := b.Complete("10m")
+
+		// assert
+		require.Equal(t, []string{"10MB", "10MiB"}, candidates)
+	})
+
+	t.Run("success - case 02: no number has no candidates", func(t *testing.T) {
+		// arrange
+		var b gocli.ByteSize
+
+		// act
+		candidates := b.Complete("")
+
+		// assert
+		require.Nil(t, candidates)
+	})
+}
+
+// TestKeyValues_Set tests the method Set of the KeyValues type.
+func TestKeyValues_Set(t *testing.T) {
+	t.Run("success - case 01: pairs separated by commas", func(t *testing.T) {
+		// arrange
+		kv := gocli.KeyValues{}
+
+		// act
+		err := kv.Set("team=core,env=prod")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, gocli.KeyValues{"team": "core", "env": "prod"}, kv)
+		require.Equal(t, "env=prod,team=core", kv.String())
+	})
+
+	t.Run("failure - case 01: pair without a key", func(t *testing.T) {
+		// arrange
+		kv := gocli.KeyValues{}
+
+		// act
+		err := kv.Set("team=core,prod")
+
+		// assert
+		require.ErrorIs(t, err, gocli.ErrInvalidKeyValue)
+		require.EqualError(t, err, "invalid key=value pair: \"prod\"")
+	})
+}
+
+// TestCIDR_Set tests the method Set of the CIDR type.
+func TestCIDR_Set(t *testing.T) {
+	t.Run("success - case 01: ipv4 network", func(t *testing.T) {
+		// arrange
+		var c gocli.CIDR
+
+		// act
+		err := c.Set("10.0.0.0/8")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), c.Prefix)
+		require.Equal(t, "10.0.0.0/8", c.String())
+	})
+
+	t.Run("failure - case 01: address without prefix length", func(t *testing.T) {
+		// arrange
+		var c gocli.CIDR
+
+		// act
+		err := c.Set("10.0.0.1")
+
+		// assert
+		require.Error(t, err)
+	})
+}
+
+// TestNewStdValue tests the function NewStdValue.
+func TestNewStdValue(t *testing.T) {
+	t.Run("success - case 01: flag.Value of the standard library", func(t *testing.T) {
+		// arrange
+		var d durationValue
+		v := gocli.NewStdValue("timeout", &d)
+
+		// act
+		err := v.Set("1m30s")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, durationValue(90*time.Second), d)
+		require.Equal(t, "1m30s", v.String())
+		require.Equal(t, "timeout", v.Type())
+	})
+
+	t.Run("success - case 02: type without name", func(t *testing.T) {
+		// arrange
+		var d durationValue
+
+		// act
+		v := gocli.NewStdValue("", &d)
+
+		// assert
+		require.Equal(t, "value", v.Type())
+	})
+}
+
+// durationValue is a flag.Value of the standard library used in the tests.
+type durationValue time.Duration
+
+// Set is the method that parses a duration.
+func (d *durationValue) Set(value string) (err error) {
+	v, err := time.ParseDuration(value)
+	*d = durationValue(v)
+	return
+}
+
+// String is the method that returns the duration as text.
+func (d *durationValue) String() (s string) {
+	s = time.Duration(*d).String()
+	return
+}